@@ -0,0 +1,208 @@
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type Cable struct {
+	ID            int                    `json:"id"`
+	URL           string                 `json:"url"`
+	Display       string                 `json:"display"`
+	Type          string                 `json:"type"`
+	ATerminations []CableTermination     `json:"a_terminations"`
+	BTerminations []CableTermination     `json:"b_terminations"`
+	Status        LabelValue             `json:"status"`
+	Tenant        *DisplayIDName         `json:"tenant"`
+	Label         string                 `json:"label"`
+	Color         string                 `json:"color"`
+	Length        *float64               `json:"length"`
+	LengthUnit    *LabelValue            `json:"length_unit"`
+	Description   string                 `json:"description"`
+	Comments      string                 `json:"comments"`
+	Tags          []Tag                  `json:"tags"`
+	CustomFields  map[string]interface{} `json:"custom_fields"`
+	Created       string                 `json:"created"`
+	LastUpdated   string                 `json:"last_updated"`
+}
+
+// CableTermination is one end of a cable.  ObjectType is the content
+// type of the terminating object, eg. "dcim.interface".
+type CableTermination struct {
+	ObjectType string       `json:"object_type"`
+	ObjectID   int          `json:"object_id"`
+	Object     *TraceObject `json:"object,omitempty"`
+}
+
+// CableOptions sets the fields of a cable created by ConnectInterfaces
+type CableOptions struct {
+	// AModel and BModel are the models of the two ends: "interface",
+	// "front-port" or "rear-port".  They default to "interface".
+	AModel string `json:"-"`
+	BModel string `json:"-"`
+
+	Status      string  `json:"status,omitempty"`
+	Type        string  `json:"type,omitempty"`
+	Label       string  `json:"label,omitempty"`
+	Color       string  `json:"color,omitempty"`
+	Length      float64 `json:"length,omitempty"`
+	LengthUnit  string  `json:"length_unit,omitempty"`
+	Tenant      *int    `json:"tenant,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Tags        []Tag   `json:"tags,omitempty"`
+}
+
+// TraceObject is an interface or port on a cable path
+type TraceObject struct {
+	ID       int            `json:"id"`
+	URL      string         `json:"url"`
+	Display  string         `json:"display"`
+	Name     string         `json:"name"`
+	Device   *DisplayIDName `json:"device"`
+	Occupied bool           `json:"_occupied"`
+}
+
+// TraceCable is a cable on a cable path
+type TraceCable struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Label   string `json:"label"`
+}
+
+// TraceHop is one segment of a cable path: the objects at the near end,
+// the cable, and the objects at the far end.  Cable is nil and Far is
+// empty where the path ends unconnected.
+type TraceHop struct {
+	Near  []TraceObject
+	Cable *TraceCable
+	Far   []TraceObject
+}
+
+// UnmarshalJSON decodes a segment as Netbox returns it, a list of near
+// end, cable and far end.  The ends are lists of objects, or single
+// objects before Netbox 3.3.
+func (h *TraceHop) UnmarshalJSON(data []byte) error {
+	var segment []json.RawMessage
+	if err := json.Unmarshal(data, &segment); err != nil {
+		return err
+	}
+	if len(segment) != 3 {
+		return fmt.Errorf("trace segment has %d elements, want 3", len(segment))
+	}
+	var err error
+	if h.Near, err = traceEnd(segment[0]); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(segment[1], &h.Cable); err != nil {
+		return err
+	}
+	h.Far, err = traceEnd(segment[2])
+	return err
+}
+
+func traceEnd(data json.RawMessage) ([]TraceObject, error) {
+	var objs []TraceObject
+	if err := json.Unmarshal(data, &objs); err == nil {
+		return objs, nil
+	}
+	var obj *TraceObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, nil
+	}
+	return []TraceObject{*obj}, nil
+}
+
+// ConnectInterfaces creates a cable between the interfaces, or ports
+// as given by opts.AModel and opts.BModel, with the given IDs
+func (c *Client) ConnectInterfaces(aID int, bID int, opts CableOptions) (Cable, error) {
+	return c.ConnectInterfacesCtx(context.Background(), aID, bID, opts)
+}
+
+// ConnectInterfacesCtx is like ConnectInterfaces but uses ctx for the request.
+func (c *Client) ConnectInterfacesCtx(ctx context.Context, aID int, bID int, opts CableOptions) (Cable, error) {
+	aEnd, err := cableTermination(opts.AModel, aID)
+	if err != nil {
+		return Cable{}, err
+	}
+	bEnd, err := cableTermination(opts.BModel, bID)
+	if err != nil {
+		return Cable{}, err
+	}
+	body := struct {
+		CableOptions
+		ATerminations []CableTermination `json:"a_terminations"`
+		BTerminations []CableTermination `json:"b_terminations"`
+	}{opts, []CableTermination{aEnd}, []CableTermination{bEnd}}
+	cable, err := createObject[Cable](ctx, c, "cable", body)
+	if err != nil {
+		return cable, err
+	}
+	c.log.Info("connect", "a", aID, "b", bID, "cable", cable.ID)
+	return cable, nil
+}
+
+func cableTermination(model string, id int) (CableTermination, error) {
+	if model == "" {
+		model = "interface"
+	}
+	switch model {
+	case "interface", "front-port", "rear-port":
+	default:
+		return CableTermination{}, fmt.Errorf("cannot connect a cable to a %s", model)
+	}
+	m, err := LookupModel(model)
+	if err != nil {
+		return CableTermination{}, err
+	}
+	return CableTermination{ObjectType: m.ContentType, ObjectID: id}, nil
+}
+
+// GetCable retrieves the cable with the given ID
+func (c *Client) GetCable(id int) (Cable, error) {
+	return c.GetCableCtx(context.Background(), id)
+}
+
+// GetCableCtx is like GetCable but uses ctx for the request.
+func (c *Client) GetCableCtx(ctx context.Context, id int) (Cable, error) {
+	return getObject[Cable](ctx, c, "cable", id)
+}
+
+// DeleteCable removes the cable from Netbox
+func (c *Client) DeleteCable(id int) error {
+	return c.DeleteCableCtx(context.Background(), id)
+}
+
+// DeleteCableCtx is like DeleteCable but uses ctx for the request.
+func (c *Client) DeleteCableCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "cable", id)
+}
+
+// TraceInterface follows the cable path from the device interface and
+// returns its segments in order
+func (c *Client) TraceInterface(id int) ([]TraceHop, error) {
+	return c.TraceInterfaceCtx(context.Background(), id)
+}
+
+// TraceInterfaceCtx is like TraceInterface but uses ctx for the request.
+func (c *Client) TraceInterfaceCtx(ctx context.Context, id int) ([]TraceHop, error) {
+	var hops []TraceHop
+	url, err := c.modelURL(OpGet, "interface", id)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.buildRequest(ctx).SetResult(&hops).Get(url + "trace/")
+	if err != nil {
+		c.log.Error("error tracing interface", "interface", id, "error", err)
+		return nil, err
+	}
+	if err = checkStatus(resp); err != nil {
+		c.log.Error("error tracing interface", "interface", id, "status", resp.StatusCode(), "error", err)
+		return nil, err
+	}
+	return hops, nil
+}
@@ -0,0 +1,94 @@
+package netbox_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestTraceHopUnmarshal(t *testing.T) {
+	data := `[
+		[[{"id": 1, "name": "eth0", "device": {"id": 10, "name": "sw1"}}], {"id": 5, "label": "c1"}, [{"id": 2, "name": "eth0"}]],
+		[{"id": 3, "name": "eth1"}, null, null]
+	]`
+	var hops []netbox.TraceHop
+	if err := json.Unmarshal([]byte(data), &hops); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(hops) != 2 {
+		t.Fatalf("Unmarshal() returned %d hops, want 2", len(hops))
+	}
+	if hops[0].Near[0].Device.Name != "sw1" || hops[0].Cable.Label != "c1" || hops[0].Far[0].ID != 2 {
+		t.Errorf("first hop = %+v", hops[0])
+	}
+	if len(hops[1].Near) != 1 || hops[1].Near[0].ID != 3 || hops[1].Cable != nil || len(hops[1].Far) != 0 {
+		t.Errorf("legacy hop = %+v", hops[1])
+	}
+	if err := json.Unmarshal([]byte(`[[1, 2]]`), &hops); err == nil {
+		t.Errorf("Unmarshal() of a short segment returned no error")
+	}
+}
+
+func TestCablesAndTrace(t *testing.T) {
+	srv, c := newClient(t)
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	device := func(name string) netboxtest.Object {
+		return srv.Add("/dcim/devices", netboxtest.Object{"name": name, "site": site["id"]})
+	}
+	sw1, sw2, panelA, panelB := device("sw1"), device("sw2"), device("panel-a"), device("panel-b")
+	port := func(dev netboxtest.Object, path string, extra netboxtest.Object) int {
+		obj := netboxtest.Object{"device": dev["id"], "name": "1", "type": "8p8c"}
+		for k, v := range extra {
+			obj[k] = v
+		}
+		return srv.Add(path, obj)["id"].(int)
+	}
+	eth1 := port(sw1, "/dcim/interfaces", netboxtest.Object{"name": "eth0", "type": "1000base-t"})
+	eth2 := port(sw2, "/dcim/interfaces", netboxtest.Object{"name": "eth0", "type": "1000base-t"})
+	rearA := port(panelA, "/dcim/rear-ports", nil)
+	frontA := port(panelA, "/dcim/front-ports", netboxtest.Object{"rear_port": rearA})
+	rearB := port(panelB, "/dcim/rear-ports", nil)
+	frontB := port(panelB, "/dcim/front-ports", netboxtest.Object{"rear_port": rearB})
+
+	if _, err := c.ConnectInterfaces(eth1, frontA, netbox.CableOptions{BModel: "front-port", Status: "connected", Label: "c1"}); err != nil {
+		t.Fatalf("ConnectInterfaces() error = %v", err)
+	}
+	trunk, err := c.ConnectInterfaces(rearA, rearB, netbox.CableOptions{AModel: "rear-port", BModel: "rear-port"})
+	if err != nil {
+		t.Fatalf("ConnectInterfaces() error = %v", err)
+	}
+	if len(trunk.ATerminations) != 1 || trunk.ATerminations[0].ObjectType != "dcim.rearport" {
+		t.Errorf("ConnectInterfaces() a_terminations = %+v", trunk.ATerminations)
+	}
+	if _, err := c.ConnectInterfaces(frontB, eth2, netbox.CableOptions{AModel: "front-port"}); err != nil {
+		t.Fatalf("ConnectInterfaces() error = %v", err)
+	}
+	if _, err := c.ConnectInterfaces(eth1, eth2, netbox.CableOptions{AModel: "device"}); err == nil {
+		t.Errorf("ConnectInterfaces() to a device returned no error")
+	}
+
+	hops, err := c.TraceInterface(eth1)
+	if err != nil {
+		t.Fatalf("TraceInterface() error = %v", err)
+	}
+	if len(hops) != 3 {
+		t.Fatalf("TraceInterface() returned %d hops, want 3: %+v", len(hops), hops)
+	}
+	last := hops[2]
+	if len(last.Far) != 1 || last.Far[0].ID != eth2 || last.Far[0].Device == nil || last.Far[0].Device.Name != "sw2" {
+		t.Errorf("TraceInterface() ends at %+v, want sw2 eth0", last.Far)
+	}
+	if hops[0].Cable == nil || hops[1].Cable == nil || hops[1].Cable.ID != trunk.ID {
+		t.Errorf("TraceInterface() cables = %+v, %+v", hops[0].Cable, hops[1].Cable)
+	}
+
+	if err := c.DeleteCable(trunk.ID); err != nil {
+		t.Fatalf("DeleteCable() error = %v", err)
+	}
+	hops, err = c.TraceInterface(eth1)
+	if err != nil || len(hops) != 2 || hops[1].Cable != nil || len(hops[1].Far) != 0 {
+		t.Errorf("TraceInterface() after delete = %+v, %v, want the path to end at the rear port", hops, err)
+	}
+}
@@ -0,0 +1,19 @@
+package netbox_test
+
+import (
+	"io"
+	"testing"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+// newClient returns a client of a new fake Netbox server.
+func newClient(t *testing.T) (*netboxtest.Server, *netbox.Client) {
+	t.Helper()
+	srv := netboxtest.NewServer("secret")
+	t.Cleanup(srv.Close)
+	return srv, netbox.NewClient(srv.URL, "secret", slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
@@ -0,0 +1,102 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+)
+
+// modelURL returns the API URL of the model's list endpoint, or of a
+// single object when an id is given, after checking that the model
+// supports op.
+func (c *Client) modelURL(op Operation, model string, id ...int) (string, error) {
+	m, err := LookupModel(model)
+	if err != nil {
+		c.log.Error("unknown model", "model", model, "error", err)
+		return "", err
+	}
+	if !m.Supports(op) {
+		return "", fmt.Errorf("%w: operation %b on model %s", ErrNotImplemented, op, model)
+	}
+	if len(id) > 0 {
+		return c.buildURL(m.Path+"/%d/", id[0]), nil
+	}
+	return c.buildURL(m.Path + "/"), nil
+}
+
+// getObject retrieves the object of the given model by ID
+func getObject[T any](ctx context.Context, c *Client, model string, id int) (T, error) {
+	var obj T
+	url, err := c.modelURL(OpGet, model, id)
+	if err != nil {
+		return obj, err
+	}
+	_, err = c.GetByURLCtx(ctx, url, &obj)
+	return obj, err
+}
+
+// findOne searches the model with args and returns the only match.
+// ErrNotFound or ErrMultipleResults is returned otherwise.
+func findOne[T any](ctx context.Context, c *Client, model string, args ...string) (T, error) {
+	var obj T
+	results := Page[T]{}
+	if err := c.SearchCtx(ctx, model, &results, args...); err != nil {
+		return obj, err
+	}
+	switch results.Count {
+	case 0:
+		return obj, ErrNotFound
+	case 1:
+		return results.Results[0], nil
+	}
+	return obj, fmt.Errorf("%w: %d", ErrMultipleResults, results.Count)
+}
+
+// createObject posts body to the model's endpoint and returns the new object
+func createObject[T any](ctx context.Context, c *Client, model string, body any) (T, error) {
+	var obj T
+	url, err := c.modelURL(OpCreate, model)
+	if err != nil {
+		return obj, err
+	}
+	r := c.buildRequest(ctx).SetResult(&obj).SetBody(body)
+	resp, err := r.Post(url)
+	if err != nil {
+		c.log.Error("error creating object", "model", model, "error", err)
+		return obj, err
+	}
+	if err = checkStatus(resp); err != nil {
+		c.log.Error("error creating object", "model", model, "status", resp.StatusCode(), "error", err)
+		return obj, err
+	}
+	return obj, nil
+}
+
+// updateObject patches the object with the fields in body and returns
+// the updated object
+func updateObject[T any](ctx context.Context, c *Client, model string, id int, body any) (T, error) {
+	var obj T
+	url, err := c.modelURL(OpUpdate, model, id)
+	if err != nil {
+		return obj, err
+	}
+	r := c.buildRequest(ctx).SetResult(&obj).SetBody(body)
+	resp, err := r.Patch(url)
+	if err != nil {
+		c.log.Error("error updating object", "model", model, "id", id, "error", err)
+		return obj, err
+	}
+	if err = checkStatus(resp); err != nil {
+		c.log.Error("error updating object", "model", model, "id", id, "status", resp.StatusCode(), "error", err)
+		return obj, err
+	}
+	return obj, nil
+}
+
+// deleteObject deletes the object of the given model by ID
+func (c *Client) deleteObject(ctx context.Context, model string, id int) error {
+	url, err := c.modelURL(OpDelete, model, id)
+	if err != nil {
+		return err
+	}
+	return c.DeleteObjectByURLCtx(ctx, url)
+}
@@ -1,27 +1,38 @@
 package netbox
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
 )
 
 func (c *Client) UpdateCustomFieldOnModel(model string, modelID int64, field string, value any) error {
+	return c.UpdateCustomFieldOnModelCtx(context.Background(), model, modelID, field, value)
+}
+
+// UpdateCustomFieldOnModelCtx is like UpdateCustomFieldOnModel but uses ctx for the request.
+func (c *Client) UpdateCustomFieldOnModelCtx(ctx context.Context, model string, modelID int64, field string, value any) error {
 	cf := make(map[string]interface{})
 	data := make(map[string]interface{})
 	cf[field] = value
 	data["custom_fields"] = cf
 
-	return c.UpdateObjectWithMap(model, modelID, data)
+	return c.UpdateObjectWithMapCtx(ctx, model, modelID, data)
 }
 
 // CustomFieldExists checks to see if a custom field exists in Netbox
 // error is set if there's a problem communicating with Netbox
 func (c *Client) CustomFieldExists(name string) (bool, error) {
+	return c.CustomFieldExistsCtx(context.Background(), name)
+}
+
+// CustomFieldExistsCtx is like CustomFieldExists but uses ctx for the request.
+func (c *Client) CustomFieldExistsCtx(ctx context.Context, name string) (bool, error) {
 	exists := false
 	field := make(map[string]interface{})
 
-	err := c.Search("customfield", &field, fmt.Sprintf("name=%s", url.QueryEscape(name)))
+	err := c.SearchCtx(ctx, "customfield", &field, fmt.Sprintf("name=%s", url.QueryEscape(name)))
 	if err != nil {
 		return exists, err
 	}
@@ -33,7 +44,7 @@ func (c *Client) CustomFieldExists(name string) (bool, error) {
 		exists = true
 	default:
 		exists = true
-		err = errors.New("too many results returned")
+		err = ErrMultipleResults
 	}
 	return exists, err
 }
@@ -45,6 +56,11 @@ func (c *Client) CustomFieldExists(name string) (bool, error) {
 //	readonly indicates if the field should be editable
 //	objects are the types of objects to attach the field to (at least 1 is requred)
 func (c *Client) AddCustomField(name string, label string, readonly bool, objects ...string) error {
+	return c.AddCustomFieldCtx(context.Background(), name, label, readonly, objects...)
+}
+
+// AddCustomFieldCtx is like AddCustomField but uses ctx for the request.
+func (c *Client) AddCustomFieldCtx(ctx context.Context, name string, label string, readonly bool, objects ...string) error {
 	data := make(map[string]interface{})
 	data["name"] = name
 	data["label"] = label
@@ -55,25 +71,32 @@ func (c *Client) AddCustomField(name string, label string, readonly bool, object
 		return errors.New("at least 1 object type must be specified")
 	}
 	for _, obj := range objects {
-		objs = append(objs, getObjectType(obj))
+		objectType, err := getObjectType(obj)
+		if err != nil {
+			c.log.Error("could not add custom field", "field", name, "error", err)
+			return err
+		}
+		objs = append(objs, objectType)
 	}
 	data["content_types"] = objs
 	data["object_types"] = objs
 	if readonly {
 		data["ui_editable"] = "no"
 	}
-	path := GetPathForModel("customfield") + "/"
-	r := c.buildRequest()
-	url := c.buildURL(path)
+	url, err := c.modelURL(OpCreate, "customfield")
+	if err != nil {
+		return err
+	}
+	r := c.buildRequest(ctx)
 	r.SetBody(data)
 	resp, err := r.Post(url)
 	if err != nil {
 		c.log.Error("could not add custom field", "field", name, "error", err)
 		return err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error("netbox returned an error", "status", resp.StatusCode(), "body", resp.Body())
-		return fmt.Errorf("%s: %s", resp.Error(), resp.Body())
+		return err
 	}
 	return nil
 }
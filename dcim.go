@@ -0,0 +1,377 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type Manufacturer struct {
+	ID              int                    `json:"id"`
+	URL             string                 `json:"url"`
+	Display         string                 `json:"display"`
+	Name            string                 `json:"name"`
+	Slug            string                 `json:"slug"`
+	Description     string                 `json:"description"`
+	Tags            []Tag                  `json:"tags"`
+	CustomFields    map[string]interface{} `json:"custom_fields"`
+	DevicetypeCount int                    `json:"devicetype_count"`
+	PlatformCount   int                    `json:"platform_count"`
+}
+
+// ManufacturerEdit is used to add/update a manufacturer
+type ManufacturerEdit struct {
+	Name         string                 `json:"name,omitempty"`
+	Slug         string                 `json:"slug,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type DeviceType struct {
+	ID              int                    `json:"id"`
+	URL             string                 `json:"url"`
+	Display         string                 `json:"display"`
+	Manufacturer    DisplayIDName          `json:"manufacturer"`
+	DefaultPlatform *DisplayIDName         `json:"default_platform"`
+	Model           string                 `json:"model"`
+	Slug            string                 `json:"slug"`
+	PartNumber      string                 `json:"part_number"`
+	UHeight         float64                `json:"u_height"`
+	IsFullDepth     bool                   `json:"is_full_depth"`
+	Airflow         *LabelValue            `json:"airflow"`
+	Weight          *float64               `json:"weight"`
+	WeightUnit      *LabelValue            `json:"weight_unit"`
+	Description     string                 `json:"description"`
+	Comments        string                 `json:"comments"`
+	Tags            []Tag                  `json:"tags"`
+	CustomFields    map[string]interface{} `json:"custom_fields"`
+	DeviceCount     int                    `json:"device_count"`
+}
+
+// DeviceTypeEdit is used to add/update a device type
+type DeviceTypeEdit struct {
+	Manufacturer    int                    `json:"manufacturer,omitempty"`
+	DefaultPlatform *int                   `json:"default_platform,omitempty"`
+	Model           string                 `json:"model,omitempty"`
+	Slug            string                 `json:"slug,omitempty"`
+	PartNumber      *string                `json:"part_number,omitempty"`
+	UHeight         *float64               `json:"u_height,omitempty"`
+	IsFullDepth     *bool                  `json:"is_full_depth,omitempty"`
+	Airflow         *string                `json:"airflow,omitempty"`
+	Weight          *float64               `json:"weight,omitempty"`
+	WeightUnit      *string                `json:"weight_unit,omitempty"`
+	Description     *string                `json:"description,omitempty"`
+	Comments        *string                `json:"comments,omitempty"`
+	Tags            []Tag                  `json:"tags,omitempty"`
+	CustomFields    map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type Platform struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Name         string                 `json:"name"`
+	Slug         string                 `json:"slug"`
+	Manufacturer *DisplayIDName         `json:"manufacturer"`
+	Description  string                 `json:"description"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	DeviceCount  int                    `json:"device_count"`
+}
+
+// PlatformEdit is used to add/update a platform
+type PlatformEdit struct {
+	Name         string                 `json:"name,omitempty"`
+	Slug         string                 `json:"slug,omitempty"`
+	Manufacturer *int                   `json:"manufacturer,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type DeviceRole struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Name         string                 `json:"name"`
+	Slug         string                 `json:"slug"`
+	Color        string                 `json:"color"`
+	VMRole       bool                   `json:"vm_role"`
+	Description  string                 `json:"description"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	DeviceCount  int                    `json:"device_count"`
+}
+
+// DeviceRoleEdit is used to add/update a device role.  Color is a hex
+// RGB value without the leading #, eg. "9e9e9e".
+type DeviceRoleEdit struct {
+	Name         string                 `json:"name,omitempty"`
+	Slug         string                 `json:"slug,omitempty"`
+	Color        string                 `json:"color,omitempty"`
+	VMRole       *bool                  `json:"vm_role,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// defaultRoleColor is the color Netbox gives new device roles
+const defaultRoleColor = "9e9e9e"
+
+// ListManufacturers returns all manufacturers that match the filter.
+// Filter needs to be given as a valid api filter (eg. name=Juniper)
+func (c *Client) ListManufacturers(filter *string) ([]Manufacturer, error) {
+	return c.ListManufacturersCtx(context.Background(), filter)
+}
+
+// ListManufacturersCtx is like ListManufacturers but uses ctx for the requests.
+func (c *Client) ListManufacturersCtx(ctx context.Context, filter *string) ([]Manufacturer, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	manufacturers, err := listAll[Manufacturer](ctx, c, "manufacturer", args)
+	if err != nil {
+		c.log.Error("error finding manufacturers", "filter", filter, "error", err)
+	}
+	return manufacturers, err
+}
+
+// GetManufacturer looks up the manufacturer by name
+func (c *Client) GetManufacturer(name string) (Manufacturer, error) {
+	return c.GetManufacturerCtx(context.Background(), name)
+}
+
+// GetManufacturerCtx is like GetManufacturer but uses ctx for the request.
+func (c *Client) GetManufacturerCtx(ctx context.Context, name string) (Manufacturer, error) {
+	return findOne[Manufacturer](ctx, c, "manufacturer", fmt.Sprintf("slug=%s", Slugify(name)))
+}
+
+// AddManufacturer creates a new manufacturer
+func (c *Client) AddManufacturer(manufacturer ManufacturerEdit) (Manufacturer, error) {
+	return c.AddManufacturerCtx(context.Background(), manufacturer)
+}
+
+// AddManufacturerCtx is like AddManufacturer but uses ctx for the request.
+func (c *Client) AddManufacturerCtx(ctx context.Context, manufacturer ManufacturerEdit) (Manufacturer, error) {
+	if manufacturer.Slug == "" {
+		manufacturer.Slug = Slugify(manufacturer.Name)
+	}
+	m, err := createObject[Manufacturer](ctx, c, "manufacturer", manufacturer)
+	if err != nil {
+		c.log.Error("could not create manufacturer", "name", manufacturer.Name, "error", err)
+	}
+	return m, err
+}
+
+// GetOrAddManufacturer will retrieve the requested manufacturer
+// by name and add it if it does not exist
+func (c *Client) GetOrAddManufacturer(name string) (Manufacturer, error) {
+	return c.GetOrAddManufacturerCtx(context.Background(), name)
+}
+
+// GetOrAddManufacturerCtx is like GetOrAddManufacturer but uses ctx for the requests.
+func (c *Client) GetOrAddManufacturerCtx(ctx context.Context, name string) (Manufacturer, error) {
+	m, err := c.GetManufacturerCtx(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddManufacturerCtx(ctx, ManufacturerEdit{Name: name})
+	}
+	return m, err
+}
+
+// ListDeviceTypes returns all device types that match the filter.
+// Filter needs to be given as a valid api filter (eg. manufacturer_id=1)
+func (c *Client) ListDeviceTypes(filter *string) ([]DeviceType, error) {
+	return c.ListDeviceTypesCtx(context.Background(), filter)
+}
+
+// ListDeviceTypesCtx is like ListDeviceTypes but uses ctx for the requests.
+func (c *Client) ListDeviceTypesCtx(ctx context.Context, filter *string) ([]DeviceType, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	types, err := listAll[DeviceType](ctx, c, "device-type", args)
+	if err != nil {
+		c.log.Error("error finding device types", "filter", filter, "error", err)
+	}
+	return types, err
+}
+
+// GetDeviceType looks up the device type by model.  Device type slugs
+// are only unique per manufacturer, so manufacturerID limits the search
+// unless it is 0.
+func (c *Client) GetDeviceType(manufacturerID int, model string) (DeviceType, error) {
+	return c.GetDeviceTypeCtx(context.Background(), manufacturerID, model)
+}
+
+// GetDeviceTypeCtx is like GetDeviceType but uses ctx for the request.
+func (c *Client) GetDeviceTypeCtx(ctx context.Context, manufacturerID int, model string) (DeviceType, error) {
+	args := []string{fmt.Sprintf("slug=%s", Slugify(model))}
+	if manufacturerID != 0 {
+		args = append(args, fmt.Sprintf("manufacturer_id=%d", manufacturerID))
+	}
+	return findOne[DeviceType](ctx, c, "device-type", args...)
+}
+
+// AddDeviceType creates a new device type
+func (c *Client) AddDeviceType(deviceType DeviceTypeEdit) (DeviceType, error) {
+	return c.AddDeviceTypeCtx(context.Background(), deviceType)
+}
+
+// AddDeviceTypeCtx is like AddDeviceType but uses ctx for the request.
+func (c *Client) AddDeviceTypeCtx(ctx context.Context, deviceType DeviceTypeEdit) (DeviceType, error) {
+	if deviceType.Slug == "" {
+		deviceType.Slug = Slugify(deviceType.Model)
+	}
+	dt, err := createObject[DeviceType](ctx, c, "device-type", deviceType)
+	if err != nil {
+		c.log.Error("could not create device type", "model", deviceType.Model, "error", err)
+	}
+	return dt, err
+}
+
+// GetOrAddDeviceType will retrieve the device type of the manufacturer
+// by model and add it, and the manufacturer, if it does not exist
+func (c *Client) GetOrAddDeviceType(manufacturer string, model string) (DeviceType, error) {
+	return c.GetOrAddDeviceTypeCtx(context.Background(), manufacturer, model)
+}
+
+// GetOrAddDeviceTypeCtx is like GetOrAddDeviceType but uses ctx for the requests.
+func (c *Client) GetOrAddDeviceTypeCtx(ctx context.Context, manufacturer string, model string) (DeviceType, error) {
+	m, err := c.GetOrAddManufacturerCtx(ctx, manufacturer)
+	if err != nil {
+		return DeviceType{}, err
+	}
+	dt, err := c.GetDeviceTypeCtx(ctx, m.ID, model)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddDeviceTypeCtx(ctx, DeviceTypeEdit{Manufacturer: m.ID, Model: model})
+	}
+	return dt, err
+}
+
+// ListPlatforms returns all platforms that match the filter.  Filter
+// needs to be given as a valid api filter (eg. manufacturer_id=1)
+func (c *Client) ListPlatforms(filter *string) ([]Platform, error) {
+	return c.ListPlatformsCtx(context.Background(), filter)
+}
+
+// ListPlatformsCtx is like ListPlatforms but uses ctx for the requests.
+func (c *Client) ListPlatformsCtx(ctx context.Context, filter *string) ([]Platform, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	platforms, err := listAll[Platform](ctx, c, "platform", args)
+	if err != nil {
+		c.log.Error("error finding platforms", "filter", filter, "error", err)
+	}
+	return platforms, err
+}
+
+// GetPlatform looks up the platform by name
+func (c *Client) GetPlatform(name string) (Platform, error) {
+	return c.GetPlatformCtx(context.Background(), name)
+}
+
+// GetPlatformCtx is like GetPlatform but uses ctx for the request.
+func (c *Client) GetPlatformCtx(ctx context.Context, name string) (Platform, error) {
+	return findOne[Platform](ctx, c, "platform", fmt.Sprintf("slug=%s", Slugify(name)))
+}
+
+// AddPlatform creates a new platform
+func (c *Client) AddPlatform(platform PlatformEdit) (Platform, error) {
+	return c.AddPlatformCtx(context.Background(), platform)
+}
+
+// AddPlatformCtx is like AddPlatform but uses ctx for the request.
+func (c *Client) AddPlatformCtx(ctx context.Context, platform PlatformEdit) (Platform, error) {
+	if platform.Slug == "" {
+		platform.Slug = Slugify(platform.Name)
+	}
+	p, err := createObject[Platform](ctx, c, "platform", platform)
+	if err != nil {
+		c.log.Error("could not create platform", "name", platform.Name, "error", err)
+	}
+	return p, err
+}
+
+// GetOrAddPlatform will retrieve the requested platform
+// by name and add it if it does not exist
+func (c *Client) GetOrAddPlatform(name string) (Platform, error) {
+	return c.GetOrAddPlatformCtx(context.Background(), name)
+}
+
+// GetOrAddPlatformCtx is like GetOrAddPlatform but uses ctx for the requests.
+func (c *Client) GetOrAddPlatformCtx(ctx context.Context, name string) (Platform, error) {
+	p, err := c.GetPlatformCtx(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddPlatformCtx(ctx, PlatformEdit{Name: name})
+	}
+	return p, err
+}
+
+// ListDeviceRoles returns all device roles that match the filter.
+// Filter needs to be given as a valid api filter (eg. vm_role=true)
+func (c *Client) ListDeviceRoles(filter *string) ([]DeviceRole, error) {
+	return c.ListDeviceRolesCtx(context.Background(), filter)
+}
+
+// ListDeviceRolesCtx is like ListDeviceRoles but uses ctx for the requests.
+func (c *Client) ListDeviceRolesCtx(ctx context.Context, filter *string) ([]DeviceRole, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	roles, err := listAll[DeviceRole](ctx, c, "device-role", args)
+	if err != nil {
+		c.log.Error("error finding device roles", "filter", filter, "error", err)
+	}
+	return roles, err
+}
+
+// GetDeviceRole looks up the device role by name
+func (c *Client) GetDeviceRole(name string) (DeviceRole, error) {
+	return c.GetDeviceRoleCtx(context.Background(), name)
+}
+
+// GetDeviceRoleCtx is like GetDeviceRole but uses ctx for the request.
+func (c *Client) GetDeviceRoleCtx(ctx context.Context, name string) (DeviceRole, error) {
+	return findOne[DeviceRole](ctx, c, "device-role", fmt.Sprintf("slug=%s", Slugify(name)))
+}
+
+// AddDeviceRole creates a new device role.  The color defaults to grey.
+func (c *Client) AddDeviceRole(role DeviceRoleEdit) (DeviceRole, error) {
+	return c.AddDeviceRoleCtx(context.Background(), role)
+}
+
+// AddDeviceRoleCtx is like AddDeviceRole but uses ctx for the request.
+func (c *Client) AddDeviceRoleCtx(ctx context.Context, role DeviceRoleEdit) (DeviceRole, error) {
+	if role.Slug == "" {
+		role.Slug = Slugify(role.Name)
+	}
+	if role.Color == "" {
+		role.Color = defaultRoleColor
+	}
+	r, err := createObject[DeviceRole](ctx, c, "device-role", role)
+	if err != nil {
+		c.log.Error("could not create device role", "name", role.Name, "error", err)
+	}
+	return r, err
+}
+
+// GetOrAddDeviceRole will retrieve the requested device role
+// by name and add it if it does not exist
+func (c *Client) GetOrAddDeviceRole(name string) (DeviceRole, error) {
+	return c.GetOrAddDeviceRoleCtx(context.Background(), name)
+}
+
+// GetOrAddDeviceRoleCtx is like GetOrAddDeviceRole but uses ctx for the requests.
+func (c *Client) GetOrAddDeviceRoleCtx(ctx context.Context, name string) (DeviceRole, error) {
+	role, err := c.GetDeviceRoleCtx(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddDeviceRoleCtx(ctx, DeviceRoleEdit{Name: name})
+	}
+	return role, err
+}
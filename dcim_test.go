@@ -0,0 +1,52 @@
+package netbox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rsapc/netbox"
+)
+
+func TestDeviceCatalog(t *testing.T) {
+	srv, c := newClient(t)
+	dt, err := c.GetOrAddDeviceType("Juniper Networks", "EX4300-48T")
+	if err != nil {
+		t.Fatalf("GetOrAddDeviceType() error = %v", err)
+	}
+	if dt.Slug != "ex4300-48t" || dt.Manufacturer.Name != "Juniper Networks" {
+		t.Errorf("GetOrAddDeviceType() = %+v", dt)
+	}
+	again, err := c.GetOrAddDeviceType("Juniper Networks", "EX4300-48T")
+	if err != nil || again.ID != dt.ID {
+		t.Errorf("GetOrAddDeviceType() again = %d, %v, want %d", again.ID, err, dt.ID)
+	}
+	if makers, err := c.ListManufacturers(nil); err != nil || len(makers) != 1 {
+		t.Errorf("ListManufacturers() = %d, %v, want 1", len(makers), err)
+	}
+	// the same model from another manufacturer is a separate type
+	other, err := c.GetOrAddDeviceType("Acme", "EX4300-48T")
+	if err != nil || other.ID == dt.ID {
+		t.Errorf("GetOrAddDeviceType() other manufacturer = %d, %v", other.ID, err)
+	}
+	if _, err := c.GetDeviceType(0, "EX4300-48T"); !errors.Is(err, netbox.ErrMultipleResults) {
+		t.Errorf("GetDeviceType() error = %v, want %v", err, netbox.ErrMultipleResults)
+	}
+
+	role, err := c.GetOrAddDeviceRole("Access Switch")
+	if err != nil || role.Slug != "access-switch" || role.Color == "" {
+		t.Errorf("GetOrAddDeviceRole() = %+v, %v", role, err)
+	}
+	if again, err := c.GetOrAddDeviceRole("access switch"); err != nil || again.ID != role.ID {
+		t.Errorf("GetOrAddDeviceRole() again = %d, %v, want %d", again.ID, err, role.ID)
+	}
+	platform, err := c.GetOrAddPlatform("Junos")
+	if err != nil {
+		t.Fatalf("GetOrAddPlatform() error = %v", err)
+	}
+	if again, err := c.GetOrAddPlatform("Junos"); err != nil || again.ID != platform.ID {
+		t.Errorf("GetOrAddPlatform() again = %d, %v, want %d", again.ID, err, platform.ID)
+	}
+	if got := len(srv.Objects("/dcim/platforms")); got != 1 {
+		t.Errorf("platforms = %d, want 1", got)
+	}
+}
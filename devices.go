@@ -0,0 +1,189 @@
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NewDevice is used to add/update a device.  Related objects are given by
+// ID, or by name: DeviceType, Role, Site, Platform and Tenant are looked
+// up by their Slugify slug, Location and Rack by name within the site.
+// Names are only resolved when the ID is 0.
+type NewDevice struct {
+	Name         string                 `json:"name,omitempty"`
+	DeviceTypeID int                    `json:"device_type,omitempty"`
+	DeviceType   string                 `json:"-"`
+	RoleID       int                    `json:"role,omitempty"`
+	Role         string                 `json:"-"`
+	SiteID       int                    `json:"site,omitempty"`
+	Site         string                 `json:"-"`
+	LocationID   int                    `json:"location,omitempty"`
+	Location     string                 `json:"-"`
+	RackID       int                    `json:"rack,omitempty"`
+	Rack         string                 `json:"-"`
+	Position     *float64               `json:"position,omitempty"`
+	Face         string                 `json:"face,omitempty"`
+	PlatformID   int                    `json:"platform,omitempty"`
+	Platform     string                 `json:"-"`
+	TenantID     int                    `json:"tenant,omitempty"`
+	Tenant       string                 `json:"-"`
+	Serial       string                 `json:"serial,omitempty"`
+	AssetTag     *string                `json:"asset_tag,omitempty"`
+	Status       string                 `json:"status,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Comments     string                 `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// MarshalJSON sends the role as both role and device_role, as Netbox
+// before 3.6 only knows the latter.
+func (d NewDevice) MarshalJSON() ([]byte, error) {
+	type device NewDevice
+	return json.Marshal(struct {
+		device
+		DeviceRole int `json:"device_role,omitempty"`
+	}{device(d), d.RoleID})
+}
+
+// resolveDevice fills in the IDs of the related objects given by name
+func (c *Client) resolveDevice(ctx context.Context, d *NewDevice) error {
+	var err error
+	bySlug := []struct {
+		model string
+		name  string
+		id    *int
+	}{
+		{"site", d.Site, &d.SiteID},
+		{"device-type", d.DeviceType, &d.DeviceTypeID},
+		{"device-role", d.Role, &d.RoleID},
+		{"platform", d.Platform, &d.PlatformID},
+		{"tenant", d.Tenant, &d.TenantID},
+	}
+	for _, ref := range bySlug {
+		if *ref.id != 0 || ref.name == "" {
+			continue
+		}
+		if *ref.id, err = c.lookupID(ctx, ref.model, fmt.Sprintf("slug=%s", Slugify(ref.name))); err != nil {
+			return err
+		}
+	}
+
+	inSite := []struct {
+		model string
+		name  string
+		id    *int
+	}{
+		{"location", d.Location, &d.LocationID},
+		{"rack", d.Rack, &d.RackID},
+	}
+	for _, ref := range inSite {
+		if *ref.id != 0 || ref.name == "" {
+			continue
+		}
+		if d.SiteID == 0 {
+			return fmt.Errorf("a site is required to find %s %s", ref.model, ref.name)
+		}
+		*ref.id, err = c.lookupID(ctx, ref.model, fmt.Sprintf("site_id=%d", d.SiteID), fmt.Sprintf("name=%s", url.QueryEscape(ref.name)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupID returns the ID of the only object of the model matching args
+func (c *Client) lookupID(ctx context.Context, model string, args ...string) (int, error) {
+	obj, err := findOne[DisplayIDName](ctx, c, model, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s %s: %w", model, strings.Join(args, "&"), err)
+	}
+	return obj.ID, nil
+}
+
+// AddDevice creates a new device
+func (c *Client) AddDevice(device NewDevice) (DeviceOrVM, error) {
+	return c.AddDeviceCtx(context.Background(), device)
+}
+
+// AddDeviceCtx is like AddDevice but uses ctx for the requests.
+func (c *Client) AddDeviceCtx(ctx context.Context, device NewDevice) (DeviceOrVM, error) {
+	if err := c.resolveDevice(ctx, &device); err != nil {
+		return DeviceOrVM{}, err
+	}
+	dev, err := createObject[DeviceOrVM](ctx, c, "device", device)
+	if err != nil {
+		c.log.Error("error adding device", "name", device.Name, "error", err)
+		return dev, err
+	}
+	setDeviceCustomFields(&dev)
+	c.log.Info("add device", "name", dev.Name, "id", dev.ID)
+	return dev, nil
+}
+
+// UpdateDevice modifies the given fields of the device
+func (c *Client) UpdateDevice(id int, device NewDevice) (DeviceOrVM, error) {
+	return c.UpdateDeviceCtx(context.Background(), id, device)
+}
+
+// UpdateDeviceCtx is like UpdateDevice but uses ctx for the requests.
+func (c *Client) UpdateDeviceCtx(ctx context.Context, id int, device NewDevice) (DeviceOrVM, error) {
+	if device.SiteID == 0 && device.Site == "" && (device.Location != "" || device.Rack != "") {
+		current, err := getObject[DeviceOrVM](ctx, c, "device", id)
+		if err != nil {
+			return current, err
+		}
+		device.SiteID = current.Site.ID
+	}
+	if err := c.resolveDevice(ctx, &device); err != nil {
+		return DeviceOrVM{}, err
+	}
+	dev, err := updateObject[DeviceOrVM](ctx, c, "device", id, device)
+	if err != nil {
+		return dev, err
+	}
+	setDeviceCustomFields(&dev)
+	return dev, nil
+}
+
+// DeleteDevice removes the device from Netbox
+func (c *Client) DeleteDevice(id int) error {
+	return c.DeleteDeviceCtx(context.Background(), id)
+}
+
+// DeleteDeviceCtx is like DeleteDevice but uses ctx for the request.
+func (c *Client) DeleteDeviceCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "device", id)
+}
+
+// GetOrAddDevice will retrieve the device by name within its site and
+// add it if it does not exist.  An existing device is returned as is.
+func (c *Client) GetOrAddDevice(device NewDevice) (DeviceOrVM, error) {
+	return c.GetOrAddDeviceCtx(context.Background(), device)
+}
+
+// GetOrAddDeviceCtx is like GetOrAddDevice but uses ctx for the requests.
+func (c *Client) GetOrAddDeviceCtx(ctx context.Context, device NewDevice) (DeviceOrVM, error) {
+	if device.Name == "" {
+		return DeviceOrVM{}, errors.New("a device name is required")
+	}
+	if err := c.resolveDevice(ctx, &device); err != nil {
+		return DeviceOrVM{}, err
+	}
+	args := []string{fmt.Sprintf("name=%s", url.QueryEscape(device.Name))}
+	if device.SiteID != 0 {
+		args = append(args, fmt.Sprintf("site_id=%d", device.SiteID))
+	}
+	dev, err := findOne[DeviceOrVM](ctx, c, "device", args...)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddDeviceCtx(ctx, device)
+	}
+	if err == nil {
+		setDeviceCustomFields(&dev)
+	}
+	return dev, err
+}
@@ -0,0 +1,60 @@
+package netbox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestDevices(t *testing.T) {
+	srv, c := newClient(t)
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	maker := srv.Add("/dcim/manufacturers", netboxtest.Object{"name": "Juniper", "slug": "juniper"})
+	dt := srv.Add("/dcim/device-types", netboxtest.Object{"manufacturer": maker["id"], "model": "EX4300", "slug": "ex4300"})
+	role := srv.Add("/dcim/device-roles", netboxtest.Object{"name": "Access Switch", "slug": "access-switch"})
+	srv.Add("/dcim/locations", netboxtest.Object{"name": "Floor 1", "slug": "floor-1", "site": site["id"]})
+	rack := srv.Add("/dcim/racks", netboxtest.Object{"name": "R1", "site": site["id"]})
+
+	position := 10.0
+	newDev := netbox.NewDevice{
+		Name:       "sw01",
+		DeviceType: "EX4300",
+		Role:       "Access Switch",
+		Site:       "HQ",
+		Location:   "Floor 1",
+		Rack:       "R1",
+		Position:   &position,
+		Face:       "front",
+		Status:     "active",
+	}
+	dev, err := c.AddDevice(newDev)
+	if err != nil {
+		t.Fatalf("AddDevice() error = %v", err)
+	}
+	if dev.DeviceType == nil || dev.DeviceType.ID != dt["id"] || dev.Role.ID != role["id"] || dev.DeviceRole.ID != role["id"] {
+		t.Errorf("AddDevice() type %+v role %+v", dev.DeviceType, dev.Role)
+	}
+	if dev.Rack.ID != rack["id"] || dev.Location == nil || dev.Location.Name != "Floor 1" || dev.Face == nil || dev.Face.Value != "front" {
+		t.Errorf("AddDevice() rack %+v location %+v face %+v", dev.Rack, dev.Location, dev.Face)
+	}
+
+	again, err := c.GetOrAddDevice(newDev)
+	if err != nil || again.ID != dev.ID {
+		t.Errorf("GetOrAddDevice() = %d, %v, want %d", again.ID, err, dev.ID)
+	}
+	updated, err := c.UpdateDevice(dev.ID, netbox.NewDevice{Serial: "ABC123"})
+	if err != nil || updated.Serial != "ABC123" || updated.Name != "sw01" {
+		t.Errorf("UpdateDevice() = %+v, %v", updated, err)
+	}
+	if _, err := c.AddDevice(netbox.NewDevice{Name: "sw02", DeviceType: "ex4300", Role: "core", Site: "hq"}); !errors.Is(err, netbox.ErrNotFound) {
+		t.Errorf("AddDevice() with an unknown role error = %v, want %v", err, netbox.ErrNotFound)
+	}
+	if err := c.DeleteDevice(dev.ID); err != nil {
+		t.Fatalf("DeleteDevice() error = %v", err)
+	}
+	if _, ok := srv.Get("/dcim/devices", dev.ID); ok {
+		t.Errorf("DeleteDevice() left the device")
+	}
+}
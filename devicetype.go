@@ -0,0 +1,278 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DeviceTypeDefinition is a device type in the format of the community
+// devicetype-library (https://github.com/netbox-community/devicetype-library).
+// Only the component kinds below are imported, others are ignored.
+type DeviceTypeDefinition struct {
+	Manufacturer string                `yaml:"manufacturer"`
+	Model        string                `yaml:"model"`
+	Slug         string                `yaml:"slug"`
+	PartNumber   string                `yaml:"part_number"`
+	UHeight      *float64              `yaml:"u_height"`
+	IsFullDepth  *bool                 `yaml:"is_full_depth"`
+	Airflow      string                `yaml:"airflow"`
+	Weight       *float64              `yaml:"weight"`
+	WeightUnit   string                `yaml:"weight_unit"`
+	Comments     string                `yaml:"comments"`
+	Interfaces   []ComponentDefinition `yaml:"interfaces"`
+	ConsolePorts []ComponentDefinition `yaml:"console-ports"`
+	PowerPorts   []ComponentDefinition `yaml:"power-ports"`
+	ModuleBays   []ComponentDefinition `yaml:"module-bays"`
+}
+
+// ComponentDefinition is an interface, console port, power port or
+// module bay of a DeviceTypeDefinition.  Fields that do not apply to the
+// kind of component are left empty.
+type ComponentDefinition struct {
+	Name          string `yaml:"name"`
+	Label         string `yaml:"label"`
+	Type          string `yaml:"type"`
+	MgmtOnly      *bool  `yaml:"mgmt_only"`
+	MaximumDraw   *int   `yaml:"maximum_draw"`
+	AllocatedDraw *int   `yaml:"allocated_draw"`
+	Position      string `yaml:"position"`
+	Description   string `yaml:"description"`
+}
+
+// ComponentTemplate is an interface, console port, power port or module
+// bay template of a device type
+type ComponentTemplate struct {
+	ID            int            `json:"id"`
+	URL           string         `json:"url"`
+	Display       string         `json:"display"`
+	DeviceType    *DisplayIDName `json:"device_type"`
+	Name          string         `json:"name"`
+	Label         string         `json:"label"`
+	Type          *LabelValue    `json:"type"`
+	MgmtOnly      bool           `json:"mgmt_only"`
+	MaximumDraw   *int           `json:"maximum_draw"`
+	AllocatedDraw *int           `json:"allocated_draw"`
+	Position      string         `json:"position"`
+	Description   string         `json:"description"`
+}
+
+// ComponentTemplateEdit is used to add/update a component template
+type ComponentTemplateEdit struct {
+	DeviceType    int     `json:"device_type,omitempty"`
+	Name          string  `json:"name,omitempty"`
+	Label         *string `json:"label,omitempty"`
+	Type          *string `json:"type,omitempty"`
+	MgmtOnly      *bool   `json:"mgmt_only,omitempty"`
+	MaximumDraw   *int    `json:"maximum_draw,omitempty"`
+	AllocatedDraw *int    `json:"allocated_draw,omitempty"`
+	Position      *string `json:"position,omitempty"`
+	Description   *string `json:"description,omitempty"`
+}
+
+// ParseDeviceType decodes a devicetype-library YAML document
+func ParseDeviceType(data []byte) (DeviceTypeDefinition, error) {
+	var def DeviceTypeDefinition
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return def, err
+	}
+	if def.Manufacturer == "" || def.Model == "" {
+		return def, errors.New("device type needs a manufacturer and model")
+	}
+	if def.Slug == "" {
+		def.Slug = Slugify(def.Model)
+	}
+	return def, nil
+}
+
+// ImportDeviceTypeFile reads the devicetype-library YAML file and imports
+// it with ImportDeviceType
+func (c *Client) ImportDeviceTypeFile(path string) (DeviceType, error) {
+	return c.ImportDeviceTypeFileCtx(context.Background(), path)
+}
+
+// ImportDeviceTypeFileCtx is like ImportDeviceTypeFile but uses ctx for the requests.
+func (c *Client) ImportDeviceTypeFileCtx(ctx context.Context, path string) (DeviceType, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DeviceType{}, err
+	}
+	def, err := ParseDeviceType(data)
+	if err != nil {
+		return DeviceType{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return c.ImportDeviceTypeCtx(ctx, def)
+}
+
+// ImportDeviceType creates the device type, and its manufacturer, or
+// updates the fields that differ from the definition.  Its interface,
+// console port, power port and module bay templates are matched by name
+// and created or updated the same way.  Templates missing from the
+// definition are left alone, so importing again changes nothing.
+func (c *Client) ImportDeviceType(def DeviceTypeDefinition) (DeviceType, error) {
+	return c.ImportDeviceTypeCtx(context.Background(), def)
+}
+
+// ImportDeviceTypeCtx is like ImportDeviceType but uses ctx for the requests.
+func (c *Client) ImportDeviceTypeCtx(ctx context.Context, def DeviceTypeDefinition) (DeviceType, error) {
+	if def.Manufacturer == "" || def.Model == "" {
+		return DeviceType{}, errors.New("device type needs a manufacturer and model")
+	}
+	if def.Slug == "" {
+		def.Slug = Slugify(def.Model)
+	}
+	m, err := c.GetOrAddManufacturerCtx(ctx, def.Manufacturer)
+	if err != nil {
+		return DeviceType{}, err
+	}
+
+	dt, err := findOne[DeviceType](ctx, c, "device-type", fmt.Sprintf("slug=%s", def.Slug), fmt.Sprintf("manufacturer_id=%d", m.ID))
+	switch {
+	case errors.Is(err, ErrNotFound):
+		edit, _ := diffDeviceType(DeviceType{}, def)
+		edit.Manufacturer = m.ID
+		edit.Slug = def.Slug
+		if dt, err = c.AddDeviceTypeCtx(ctx, edit); err != nil {
+			return dt, err
+		}
+		c.log.Info("add device type", "model", dt.Model, "id", dt.ID)
+	case err != nil:
+		return dt, err
+	default:
+		if edit, changed := diffDeviceType(dt, def); changed {
+			if dt, err = updateObject[DeviceType](ctx, c, "device-type", dt.ID, edit); err != nil {
+				c.log.Error("error updating device type", "model", def.Model, "error", err)
+				return dt, err
+			}
+			c.log.Info("update device type", "model", dt.Model, "id", dt.ID)
+		}
+	}
+
+	templates := []struct {
+		model string
+		defs  []ComponentDefinition
+	}{
+		{"interface-template", def.Interfaces},
+		{"console-port-template", def.ConsolePorts},
+		{"power-port-template", def.PowerPorts},
+		{"module-bay-template", def.ModuleBays},
+	}
+	for _, t := range templates {
+		if err = c.syncTemplates(ctx, t.model, dt.ID, t.defs); err != nil {
+			return dt, fmt.Errorf("%s: %w", t.model, err)
+		}
+	}
+	return dt, nil
+}
+
+// diffDeviceType returns an edit holding the fields of def that differ
+// from dt, and whether there are any
+func diffDeviceType(dt DeviceType, def DeviceTypeDefinition) (DeviceTypeEdit, bool) {
+	edit := DeviceTypeEdit{}
+	changed := false
+	if def.Model != dt.Model {
+		edit.Model = def.Model
+		changed = true
+	}
+	if def.PartNumber != "" && def.PartNumber != dt.PartNumber {
+		edit.PartNumber = &def.PartNumber
+		changed = true
+	}
+	if def.UHeight != nil && *def.UHeight != dt.UHeight {
+		edit.UHeight = def.UHeight
+		changed = true
+	}
+	if def.IsFullDepth != nil && *def.IsFullDepth != dt.IsFullDepth {
+		edit.IsFullDepth = def.IsFullDepth
+		changed = true
+	}
+	if def.Airflow != "" && (dt.Airflow == nil || dt.Airflow.Value != def.Airflow) {
+		edit.Airflow = &def.Airflow
+		changed = true
+	}
+	if def.Weight != nil && (dt.Weight == nil || *dt.Weight != *def.Weight) {
+		edit.Weight = def.Weight
+		changed = true
+	}
+	if def.WeightUnit != "" && (dt.WeightUnit == nil || dt.WeightUnit.Value != def.WeightUnit) {
+		edit.WeightUnit = &def.WeightUnit
+		changed = true
+	}
+	if def.Comments != "" && def.Comments != dt.Comments {
+		edit.Comments = &def.Comments
+		changed = true
+	}
+	return edit, changed
+}
+
+// syncTemplates creates or updates the templates of the model for the
+// device type to match defs
+func (c *Client) syncTemplates(ctx context.Context, model string, deviceTypeID int, defs []ComponentDefinition) error {
+	if len(defs) == 0 {
+		return nil
+	}
+	current, err := listAll[ComponentTemplate](ctx, c, model, fmt.Sprintf("device_type_id=%d", deviceTypeID))
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]ComponentTemplate)
+	for _, t := range current {
+		byName[t.Name] = t
+	}
+	for _, def := range defs {
+		t, ok := byName[def.Name]
+		edit, changed := diffTemplate(t, def)
+		switch {
+		case !ok:
+			edit.DeviceType = deviceTypeID
+			edit.Name = def.Name
+			_, err = createObject[ComponentTemplate](ctx, c, model, edit)
+		case changed:
+			_, err = updateObject[ComponentTemplate](ctx, c, model, t.ID, edit)
+		}
+		if err != nil {
+			c.log.Error("error importing template", "model", model, "name", def.Name, "error", err)
+			return fmt.Errorf("%s: %w", def.Name, err)
+		}
+	}
+	return nil
+}
+
+// diffTemplate returns an edit holding the fields of def that differ
+// from t, and whether there are any
+func diffTemplate(t ComponentTemplate, def ComponentDefinition) (ComponentTemplateEdit, bool) {
+	edit := ComponentTemplateEdit{}
+	changed := false
+	if def.Label != "" && def.Label != t.Label {
+		edit.Label = &def.Label
+		changed = true
+	}
+	if def.Type != "" && (t.Type == nil || t.Type.Value != def.Type) {
+		edit.Type = &def.Type
+		changed = true
+	}
+	if def.MgmtOnly != nil && *def.MgmtOnly != t.MgmtOnly {
+		edit.MgmtOnly = def.MgmtOnly
+		changed = true
+	}
+	if def.MaximumDraw != nil && (t.MaximumDraw == nil || *t.MaximumDraw != *def.MaximumDraw) {
+		edit.MaximumDraw = def.MaximumDraw
+		changed = true
+	}
+	if def.AllocatedDraw != nil && (t.AllocatedDraw == nil || *t.AllocatedDraw != *def.AllocatedDraw) {
+		edit.AllocatedDraw = def.AllocatedDraw
+		changed = true
+	}
+	if def.Position != "" && def.Position != t.Position {
+		edit.Position = &def.Position
+		changed = true
+	}
+	if def.Description != "" && def.Description != t.Description {
+		edit.Description = &def.Description
+		changed = true
+	}
+	return edit, changed
+}
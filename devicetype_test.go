@@ -0,0 +1,146 @@
+package netbox_test
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/rsapc/netbox"
+)
+
+func TestParseDeviceType(t *testing.T) {
+	data, err := os.ReadFile("testdata/juniper-ex4300-48t.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := netbox.ParseDeviceType(data)
+	if err != nil {
+		t.Fatalf("ParseDeviceType() error = %v", err)
+	}
+	if def.Manufacturer != "Juniper" || def.Model != "EX4300-48T" || def.Slug != "juniper-ex4300-48t" || def.UHeight == nil || *def.UHeight != 1 {
+		t.Errorf("ParseDeviceType() = %+v", def)
+	}
+	if len(def.Interfaces) != 4 || len(def.ConsolePorts) != 1 || len(def.PowerPorts) != 2 || len(def.ModuleBays) != 1 {
+		t.Errorf("ParseDeviceType() components = %d interfaces, %d console ports, %d power ports, %d module bays",
+			len(def.Interfaces), len(def.ConsolePorts), len(def.PowerPorts), len(def.ModuleBays))
+	}
+	if em0 := def.Interfaces[0]; em0.Name != "em0" || em0.Type != "1000base-t" || em0.MgmtOnly == nil || !*em0.MgmtOnly {
+		t.Errorf("ParseDeviceType() em0 = %+v", em0)
+	}
+	if psu := def.PowerPorts[0]; psu.MaximumDraw == nil || *psu.MaximumDraw != 350 {
+		t.Errorf("ParseDeviceType() PSU0 = %+v", psu)
+	}
+	if bay := def.ModuleBays[0]; bay.Position != "2" {
+		t.Errorf("ParseDeviceType() module bay = %+v", bay)
+	}
+
+	def, err = netbox.ParseDeviceType([]byte("manufacturer: Acme\nmodel: Widget 9000\n"))
+	if err != nil || def.Slug != "widget-9000" {
+		t.Errorf("ParseDeviceType() without a slug = %q, %v, want widget-9000", def.Slug, err)
+	}
+	if _, err := netbox.ParseDeviceType([]byte("model: Widget\n")); err == nil {
+		t.Errorf("ParseDeviceType() without a manufacturer succeeded")
+	}
+	if _, err := netbox.ParseDeviceType([]byte("model: [")); err == nil {
+		t.Errorf("ParseDeviceType() of invalid YAML succeeded")
+	}
+}
+
+func TestDiffTemplate(t *testing.T) {
+	yes, draw := true, 350
+	current := netbox.ComponentTemplate{
+		ID:          1,
+		Name:        "em0",
+		Type:        &netbox.LabelValue{Value: "1000base-t"},
+		MgmtOnly:    true,
+		MaximumDraw: &draw,
+	}
+	tests := []struct {
+		name    string
+		def     netbox.ComponentDefinition
+		changed bool
+	}{
+		{name: "same", def: netbox.ComponentDefinition{Name: "em0", Type: "1000base-t", MgmtOnly: &yes, MaximumDraw: &draw}, changed: false},
+		{name: "unset fields are kept", def: netbox.ComponentDefinition{Name: "em0"}, changed: false},
+		{name: "type", def: netbox.ComponentDefinition{Name: "em0", Type: "10gbase-x-sfpp"}, changed: true},
+		{name: "label", def: netbox.ComponentDefinition{Name: "em0", Label: "MGMT"}, changed: true},
+		{name: "position", def: netbox.ComponentDefinition{Name: "em0", Position: "1"}, changed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit, changed := netbox.DiffTemplate(current, tt.def)
+			if changed != tt.changed {
+				t.Errorf("DiffTemplate() = %+v, %v, want changed %v", edit, changed, tt.changed)
+			}
+			if changed && edit.DeviceType != 0 {
+				t.Errorf("DiffTemplate() edit sets the device type: %+v", edit)
+			}
+		})
+	}
+	edit, _ := netbox.DiffTemplate(current, netbox.ComponentDefinition{Name: "em0", Type: "10gbase-x-sfpp", MgmtOnly: &yes})
+	if edit.Type == nil || *edit.Type != "10gbase-x-sfpp" || edit.MgmtOnly != nil || edit.Label != nil {
+		t.Errorf("DiffTemplate() = %+v, want only the type", edit)
+	}
+}
+
+func TestImportDeviceType(t *testing.T) {
+	srv, c := newClient(t)
+	counts := func() []int {
+		return []int{
+			len(srv.Objects("/dcim/manufacturers")),
+			len(srv.Objects("/dcim/device-types")),
+			len(srv.Objects("/dcim/interface-templates")),
+			len(srv.Objects("/dcim/console-port-templates")),
+			len(srv.Objects("/dcim/power-port-templates")),
+			len(srv.Objects("/dcim/module-bay-templates")),
+		}
+	}
+
+	dt, err := c.ImportDeviceTypeFile("testdata/juniper-ex4300-48t.yaml")
+	if err != nil {
+		t.Fatalf("ImportDeviceTypeFile() error = %v", err)
+	}
+	if dt.Slug != "juniper-ex4300-48t" || dt.Manufacturer.Name != "Juniper" || dt.UHeight != 1 || !dt.IsFullDepth || dt.Airflow.Value != "front-to-rear" {
+		t.Errorf("ImportDeviceTypeFile() = %+v", dt)
+	}
+	want := []int{1, 1, 4, 1, 2, 1}
+	if got := counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("objects after import = %v, want %v", got, want)
+	}
+
+	again, err := c.ImportDeviceTypeFile("testdata/juniper-ex4300-48t.yaml")
+	if err != nil || again.ID != dt.ID {
+		t.Fatalf("ImportDeviceTypeFile() again = %d, %v, want %d", again.ID, err, dt.ID)
+	}
+	if got := counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("objects after second import = %v, want %v", got, want)
+	}
+
+	rev2, err := c.ImportDeviceTypeFile("testdata/juniper-ex4300-48t-rev2.yaml")
+	if err != nil || rev2.ID != dt.ID {
+		t.Fatalf("ImportDeviceTypeFile() rev2 = %d, %v, want %d", rev2.ID, err, dt.ID)
+	}
+	if rev2.PartNumber != "EX4300-48T-AFI" || rev2.Airflow.Value != "rear-to-front" {
+		t.Errorf("ImportDeviceTypeFile() rev2 = %+v", rev2)
+	}
+	// the module bay missing from rev2 is kept
+	want = []int{1, 1, 4, 2, 2, 1}
+	if got := counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("objects after rev2 import = %v, want %v", got, want)
+	}
+	for _, psu := range srv.Objects("/dcim/power-port-templates") {
+		if fmt.Sprint(psu["maximum_draw"]) != "450" {
+			t.Errorf("%s maximum_draw = %v, want 450", psu["name"], psu["maximum_draw"])
+		}
+	}
+	for _, intf := range srv.Objects("/dcim/interface-templates") {
+		if intf["name"] == "em0" && (intf["label"] != "MGMT" || intf["mgmt_only"] != true) {
+			t.Errorf("em0 = %v", intf)
+		}
+	}
+
+	if _, err := c.ImportDeviceTypeFile("testdata/missing.yaml"); err == nil {
+		t.Errorf("ImportDeviceTypeFile() of a missing file succeeded")
+	}
+}
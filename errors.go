@@ -0,0 +1,133 @@
+package netbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+var ErrNotFound = errors.New("the requested object was not found")
+var ErrNotImplemented = errors.New("not implemented")
+
+// ErrMultipleResults is returned when a lookup expected a single object
+// but Netbox matched more than one.
+var ErrMultipleResults = errors.New("too many results returned")
+
+// Sentinel errors matched by an *APIError with the corresponding status
+// code, for use with errors.Is.
+var (
+	ErrBadRequest   = errors.New("netbox rejected the request")
+	ErrUnauthorized = errors.New("netbox authentication failed")
+	ErrForbidden    = errors.New("netbox denied permission")
+	ErrConflict     = errors.New("netbox reported a conflict")
+	ErrRateLimited  = errors.New("netbox rate limit exceeded")
+	ErrServer       = errors.New("netbox server error")
+)
+
+// APIError describes a non-2xx response from Netbox.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	// Detail is the "detail" message Netbox includes for authentication,
+	// permission and not found errors.
+	Detail string
+	// FieldErrors holds validation messages keyed by field name, decoded
+	// from a {"field": ["msg"]} response body.
+	FieldErrors map[string][]string
+	// RequestID is the X-Request-ID header Netbox attaches to each response.
+	RequestID string
+	Body      []byte
+}
+
+func (e *APIError) Error() string {
+	msg := strings.TrimSpace(string(e.Body))
+	if e.Detail != "" {
+		msg = e.Detail
+	} else if len(e.FieldErrors) > 0 {
+		fields := make([]string, 0, len(e.FieldErrors))
+		for field := range e.FieldErrors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		var parts []string
+		for _, field := range fields {
+			parts = append(parts, fmt.Sprintf("%s: %s", field, strings.Join(e.FieldErrors[field], " ")))
+		}
+		msg = strings.Join(parts, "; ")
+	}
+	return fmt.Sprintf("invalid response to %s %s: [%d] %s", e.Method, e.URL, e.StatusCode, msg)
+}
+
+// Is reports whether the status code of e corresponds to target, so that
+// errors.Is(err, ErrNotFound) matches a 404 and so on.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// newAPIError builds an APIError from the response, decoding any detail
+// or field errors in the body.
+func newAPIError(resp *resty.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode(),
+		RequestID:  resp.Header().Get("X-Request-ID"),
+		Body:       resp.Body(),
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL
+	}
+	body := make(map[string]json.RawMessage)
+	if json.Unmarshal(e.Body, &body) != nil {
+		return e
+	}
+	for field, raw := range body {
+		var msgs []string
+		var msg string
+		switch {
+		case field == "detail" && json.Unmarshal(raw, &msg) == nil:
+			e.Detail = msg
+		case json.Unmarshal(raw, &msgs) == nil:
+			addFieldError(e, field, msgs...)
+		case json.Unmarshal(raw, &msg) == nil:
+			addFieldError(e, field, msg)
+		}
+	}
+	return e
+}
+
+func addFieldError(e *APIError, field string, msgs ...string) {
+	if e.FieldErrors == nil {
+		e.FieldErrors = make(map[string][]string)
+	}
+	e.FieldErrors[field] = append(e.FieldErrors[field], msgs...)
+}
+
+// checkStatus returns an *APIError if Netbox responded with an error status.
+func checkStatus(resp *resty.Response) error {
+	if resp.IsError() {
+		return newAPIError(resp)
+	}
+	return nil
+}
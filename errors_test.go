@@ -0,0 +1,74 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		want        error
+		detail      string
+		fieldErrors map[string][]string
+	}{
+		{
+			name:        "validation error",
+			status:      http.StatusBadRequest,
+			body:        `{"name": ["This field is required."], "slug": "Enter a valid slug."}`,
+			want:        ErrBadRequest,
+			fieldErrors: map[string][]string{"name": {"This field is required."}, "slug": {"Enter a valid slug."}},
+		},
+		{
+			name:   "bad token",
+			status: http.StatusForbidden,
+			body:   `{"detail": "Invalid token"}`,
+			want:   ErrForbidden,
+			detail: "Invalid token",
+		},
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			body:   `{"detail": "Not found."}`,
+			want:   ErrNotFound,
+			detail: "Not found.",
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			body:   `<html>Server Error</html>`,
+			want:   ErrServer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-ID", "abc-123")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			_, err := c.GetByURLCtx(context.Background(), c.buildURL("/dcim/devices/1/"), &DeviceOrVM{})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("GetByURLCtx() error = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetByURLCtx() error is %T, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Method != http.MethodGet || apiErr.RequestID != "abc-123" {
+				t.Errorf("APIError = %+v", apiErr)
+			}
+			if apiErr.Detail != tt.detail {
+				t.Errorf("APIError.Detail = %q, want %q", apiErr.Detail, tt.detail)
+			}
+			if !reflect.DeepEqual(apiErr.FieldErrors, tt.fieldErrors) {
+				t.Errorf("APIError.FieldErrors = %v, want %v", apiErr.FieldErrors, tt.fieldErrors)
+			}
+		})
+	}
+}
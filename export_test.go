@@ -0,0 +1,8 @@
+package netbox
+
+// Unexported helpers used by the tests in package netbox_test.
+var (
+	DiffTemplate      = diffTemplate
+	PlanInterfaceSync = planInterfaceSync
+	DiffInterface     = diffInterface
+)
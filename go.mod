@@ -6,6 +6,7 @@ require (
 	github.com/go-resty/resty/v2 v2.11.0
 	github.com/rsapc/hookcmd v0.0.0-20240228165245-7a165828a6f1
 	golang.org/x/exp v0.0.0-20240222234643-814bf88cf225
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require golang.org/x/net v0.19.0 // indirect
@@ -0,0 +1,385 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InterfaceSyncAction is a change SyncInterfaces makes to an interface.
+type InterfaceSyncAction string
+
+// InterfaceSyncActions
+const (
+	InterfaceCreate  InterfaceSyncAction = "create"
+	InterfaceUpdate  InterfaceSyncAction = "update"
+	InterfaceDisable InterfaceSyncAction = "disable"
+	InterfaceDelete  InterfaceSyncAction = "delete"
+)
+
+// StaleInterfacePolicy decides what SyncInterfaces does with interfaces
+// of the device or virtualmachine that are no longer present.
+type StaleInterfacePolicy int
+
+// StaleInterfacePolicies
+const (
+	KeepStaleInterfaces StaleInterfacePolicy = iota
+	DisableStaleInterfaces
+	DeleteStaleInterfaces
+)
+
+// InterfaceSyncOptions controls SyncInterfaces.
+type InterfaceSyncOptions struct {
+	// DryRun plans the changes without making them.
+	DryRun bool
+	// MatchMAC matches a desired interface to an existing one with the
+	// same MAC address when no interface has its name, so renamed
+	// interfaces are updated rather than recreated.
+	MatchMAC bool
+	// Stale is applied to interfaces not matched by any desired one.
+	Stale StaleInterfacePolicy
+}
+
+// InterfaceChange is a change planned or made by SyncInterfaces.
+type InterfaceChange struct {
+	Action InterfaceSyncAction
+	// Name is the interface name after the change.
+	Name string
+	// ID is the interface changed.  It is 0 for planned creations.
+	ID int
+	// Edit holds the fields sent to Netbox.
+	Edit InterfaceEdit
+	// Err is set if the change failed.
+	Err error
+}
+
+// SyncInterfaces reconciles the interfaces of a device or virtualmachine
+// with the desired ones.  Interfaces are matched by name, or by MAC
+// address with opts.MatchMAC, and only fields set in the desired edit
+// that differ from Netbox are updated.  Missing interfaces are created,
+// and ParentName and LagName are resolved among the object's interfaces,
+// including ones created by the sync.  Stale interfaces are deleted
+// after their child and LAG member interfaces.
+//
+// The changes are returned with any failures joined in the error.  With
+// opts.DryRun nothing is changed.
+func (c *Client) SyncInterfaces(netboxType string, objectID int64, desired []InterfaceEdit, opts InterfaceSyncOptions) ([]InterfaceChange, error) {
+	return c.SyncInterfacesCtx(context.Background(), netboxType, objectID, desired, opts)
+}
+
+// SyncInterfacesCtx is like SyncInterfaces but uses ctx for the requests.
+func (c *Client) SyncInterfacesCtx(ctx context.Context, netboxType string, objectID int64, desired []InterfaceEdit, opts InterfaceSyncOptions) ([]InterfaceChange, error) {
+	current, err := c.GetInterfacesForObjectCtx(ctx, netboxType, objectID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := planInterfaceSync(current, desired, opts)
+	if err != nil || opts.DryRun {
+		return changes, err
+	}
+
+	ids := make(map[string]int)
+	for _, intf := range current {
+		ids[intf.Name] = intf.ID
+	}
+	// Apply changes whose parent and LAG are known, repeating until no
+	// more can be applied, so interfaces are created before their children.
+	pending := make([]*InterfaceChange, 0, len(changes))
+	for i := range changes {
+		pending = append(pending, &changes[i])
+	}
+	for len(pending) > 0 {
+		var waiting []*InterfaceChange
+		for _, change := range pending {
+			if !resolveInterfaceRefs(&change.Edit, ids) {
+				waiting = append(waiting, change)
+				continue
+			}
+			change.Err = c.applyInterfaceChange(ctx, netboxType, objectID, change)
+			if change.Err == nil && change.ID != 0 {
+				ids[change.Name] = change.ID
+			}
+		}
+		if len(waiting) == len(pending) {
+			for _, change := range waiting {
+				change.Err = fmt.Errorf("parent %q or lag %q: %w", change.Edit.ParentName, change.Edit.LagName, ErrNotFound)
+			}
+			break
+		}
+		pending = waiting
+	}
+
+	var errs []error
+	for _, change := range changes {
+		if change.Err != nil {
+			c.log.Error("error syncing interface", "action", change.Action, "interface", change.Name, "error", change.Err)
+			errs = append(errs, fmt.Errorf("%s %s: %w", change.Action, change.Name, change.Err))
+		}
+	}
+	return changes, errors.Join(errs...)
+}
+
+func planInterfaceSync(current []Interface, desired []InterfaceEdit, opts InterfaceSyncOptions) ([]InterfaceChange, error) {
+	byName := make(map[string]Interface)
+	byMAC := make(map[string]Interface)
+	for _, intf := range current {
+		byName[intf.Name] = intf
+		if mac := strings.ToLower(intf.GetMacAddress()); mac != "" {
+			byMAC[mac] = intf
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, want := range desired {
+		if want.Name == nil || *want.Name == "" {
+			return nil, errors.New("every desired interface needs a name")
+		}
+		wanted[*want.Name] = true
+	}
+
+	// An interface is only matched once.  Matching by name takes
+	// precedence, so an interface named by a desired one is not renamed
+	// to another with its MAC address.
+	var changes []InterfaceChange
+	matched := make(map[int]bool)
+	for _, want := range desired {
+		intf, ok := byName[*want.Name]
+		ok = ok && !matched[intf.ID]
+		if !ok && opts.MatchMAC && want.MacAddress != nil {
+			intf, ok = byMAC[strings.ToLower(*want.MacAddress)]
+			ok = ok && !matched[intf.ID] && !wanted[intf.Name]
+		}
+		if !ok {
+			changes = append(changes, InterfaceChange{Action: InterfaceCreate, Name: *want.Name, Edit: want})
+			continue
+		}
+		matched[intf.ID] = true
+		if edit, changed := diffInterface(intf, want); changed {
+			changes = append(changes, InterfaceChange{Action: InterfaceUpdate, Name: *want.Name, ID: intf.ID, Edit: edit})
+		}
+	}
+
+	if opts.Stale == KeepStaleInterfaces {
+		return changes, nil
+	}
+	var stale []Interface
+	for _, intf := range current {
+		if !matched[intf.ID] {
+			stale = append(stale, intf)
+		}
+	}
+	// Netbox refuses to delete a parent or LAG with child or member
+	// interfaces, so those come first.
+	depth := interfaceDepths(current)
+	sort.Slice(stale, func(i, j int) bool {
+		if di, dj := depth[stale[i].ID], depth[stale[j].ID]; di != dj {
+			return di > dj
+		}
+		return stale[i].Name < stale[j].Name
+	})
+	for _, intf := range stale {
+		switch {
+		case opts.Stale == DeleteStaleInterfaces:
+			changes = append(changes, InterfaceChange{Action: InterfaceDelete, Name: intf.Name, ID: intf.ID})
+		case intf.Enabled:
+			changes = append(changes, InterfaceChange{Action: InterfaceDisable, Name: intf.Name, ID: intf.ID})
+		}
+	}
+	return changes, nil
+}
+
+// interfaceDepths returns the number of parent and LAG interfaces above
+// each interface, following the longest chain.
+func interfaceDepths(intfs []Interface) map[int]int {
+	byID := make(map[int]Interface)
+	for _, intf := range intfs {
+		byID[intf.ID] = intf
+	}
+	depth := make(map[int]int)
+	visiting := make(map[int]bool)
+	var walk func(id int) int
+	walk = func(id int) int {
+		if d, ok := depth[id]; ok {
+			return d
+		}
+		intf, ok := byID[id]
+		if !ok || visiting[id] {
+			return 0
+		}
+		visiting[id] = true
+		d := 0
+		for _, up := range []*NestedInterface{intf.Parent, intf.Lag} {
+			if up != nil {
+				d = max(d, walk(up.ID)+1)
+			}
+		}
+		depth[id] = d
+		return d
+	}
+	for _, intf := range intfs {
+		walk(intf.ID)
+	}
+	return depth
+}
+
+// diffInterface returns an edit holding the fields of want that differ
+// from intf, and whether there are any
+func diffInterface(intf Interface, want InterfaceEdit) (InterfaceEdit, bool) {
+	edit := InterfaceEdit{}
+	changed := false
+	if want.Name != nil && *want.Name != intf.Name {
+		edit.Name = want.Name
+		changed = true
+	}
+	if (want.Description != "" || want.clearDescription) && want.Description != intf.Description {
+		edit.SetDescription(want.Description)
+		changed = true
+	}
+	if want.Label != nil && *want.Label != intf.Label {
+		edit.Label = want.Label
+		changed = true
+	}
+	if want.Type != nil && *want.Type != intf.Type.Value {
+		edit.Type = want.Type
+		changed = true
+	}
+	if want.Speed != nil && *want.Speed != intf.GetSpeed() {
+		edit.Speed = want.Speed
+		changed = true
+	}
+	duplex := InterfaceEdit{}
+	if duplex.SetDuplex(want.Duplex) && *duplex.Duplex != intf.GetDuplex() {
+		edit.Duplex = duplex.Duplex
+		changed = true
+	}
+	if want.MacAddress != nil && !strings.EqualFold(*want.MacAddress, intf.GetMacAddress()) {
+		edit.MacAddress = want.MacAddress
+		changed = true
+	}
+	if want.Mode != nil && (intf.Mode == nil || intf.Mode.Value != *want.Mode) {
+		edit.Mode = want.Mode
+		changed = true
+	}
+	if want.UntaggedVlan != nil && (intf.UntaggedVlan == nil || intf.UntaggedVlan.ID != *want.UntaggedVlan) {
+		edit.UntaggedVlan = want.UntaggedVlan
+		changed = true
+	}
+	if want.TaggedVlans != nil && !sameVlans(intf.TaggedVlans, want.TaggedVlans) {
+		edit.TaggedVlans = want.TaggedVlans
+		changed = true
+	}
+	if want.Parent != nil && (intf.Parent == nil || intf.Parent.ID != *want.Parent) {
+		edit.Parent = want.Parent
+		changed = true
+	}
+	if want.Lag != nil && (intf.Lag == nil || intf.Lag.ID != *want.Lag) {
+		edit.Lag = want.Lag
+		changed = true
+	}
+	if want.Enabled != nil && *want.Enabled != intf.Enabled {
+		edit.Enabled = want.Enabled
+		changed = true
+	}
+	if want.Mtu != nil && (intf.Mtu == nil || *intf.Mtu != *want.Mtu) {
+		edit.Mtu = want.Mtu
+		changed = true
+	}
+	if want.MgmtOnly != nil && *want.MgmtOnly != intf.MgmtOnly {
+		edit.MgmtOnly = want.MgmtOnly
+		changed = true
+	}
+	if want.Vrf != nil && (intf.Vrf == nil || intf.Vrf.ID != *want.Vrf) {
+		edit.Vrf = want.Vrf
+		changed = true
+	}
+	if want.Tags != nil && !sameTags(intf.Tags, want.Tags) {
+		edit.Tags = want.Tags
+		changed = true
+	}
+	for name, value := range want.CustomFields {
+		if current, ok := intf.CustomFields[name]; !ok || fmt.Sprint(current) != fmt.Sprint(value) {
+			edit.SetCustomField(name, value)
+			changed = true
+		}
+	}
+	if want.ParentName != "" && (intf.Parent == nil || intf.Parent.Name != want.ParentName) {
+		edit.ParentName = want.ParentName
+		changed = true
+	}
+	if want.LagName != "" && (intf.Lag == nil || intf.Lag.Name != want.LagName) {
+		edit.LagName = want.LagName
+		changed = true
+	}
+	return edit, changed
+}
+
+func sameVlans(current []NestedVLAN, want []int) bool {
+	if len(current) != len(want) {
+		return false
+	}
+	ids := make(map[int]bool)
+	for _, vlan := range current {
+		ids[vlan.ID] = true
+	}
+	for _, id := range want {
+		if !ids[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameTags(current []Tag, want []Tag) bool {
+	if len(current) != len(want) {
+		return false
+	}
+	slugs := make(map[string]bool)
+	for _, tag := range current {
+		slugs[tag.Slug] = true
+	}
+	for _, tag := range want {
+		if !slugs[tag.Slug] {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveInterfaceRefs sets the parent and LAG of the edit from their
+// names.  It returns false if a name is not known yet.
+func resolveInterfaceRefs(edit *InterfaceEdit, ids map[string]int) bool {
+	if edit.Parent == nil && edit.ParentName != "" {
+		id, ok := ids[edit.ParentName]
+		if !ok {
+			return false
+		}
+		edit.SetParent(id)
+	}
+	if edit.Lag == nil && edit.LagName != "" {
+		id, ok := ids[edit.LagName]
+		if !ok {
+			return false
+		}
+		edit.SetLag(id)
+	}
+	return true
+}
+
+func (c *Client) applyInterfaceChange(ctx context.Context, netboxType string, objectID int64, change *InterfaceChange) error {
+	switch change.Action {
+	case InterfaceCreate:
+		intf, err := c.AddInterfaceCtx(ctx, netboxType, objectID, change.Edit)
+		change.ID = intf.ID
+		return err
+	case InterfaceUpdate:
+		return c.UpdateInterfaceCtx(ctx, netboxType, int64(change.ID), change.Edit)
+	case InterfaceDisable:
+		change.Edit.SetEnabled(false)
+		return c.UpdateInterfaceCtx(ctx, netboxType, int64(change.ID), change.Edit)
+	case InterfaceDelete:
+		return c.DeleteInterfaceCtx(ctx, netboxType, int64(change.ID))
+	}
+	return fmt.Errorf("unknown action %s", change.Action)
+}
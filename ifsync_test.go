@@ -0,0 +1,203 @@
+package netbox_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestPlanInterfaceSyncMatchesOnce(t *testing.T) {
+	mac := "00:00:00:00:00:bb"
+	current := []netbox.Interface{
+		{ID: 1, Name: "eth0", Enabled: true},
+		{ID: 2, Name: "eth1", Enabled: true, MacAddress: &mac},
+	}
+	edit := func(name string, mac string) netbox.InterfaceEdit {
+		e := netbox.InterfaceEdit{}
+		e.SetName(name)
+		if mac != "" {
+			e.SetMac(mac)
+		}
+		return e
+	}
+	tests := []struct {
+		name    string
+		desired []netbox.InterfaceEdit
+		want    []netbox.InterfaceChange
+	}{
+		{
+			name:    "name before MAC",
+			desired: []netbox.InterfaceEdit{edit("eth1", ""), edit("ens1", mac)},
+			want:    []netbox.InterfaceChange{{Action: netbox.InterfaceCreate, Name: "ens1"}},
+		},
+		{
+			name:    "MAC before name",
+			desired: []netbox.InterfaceEdit{edit("ens1", mac), edit("eth1", "")},
+			want:    []netbox.InterfaceChange{{Action: netbox.InterfaceCreate, Name: "ens1"}},
+		},
+		{
+			name:    "renamed by MAC",
+			desired: []netbox.InterfaceEdit{edit("ens1", mac)},
+			want:    []netbox.InterfaceChange{{Action: netbox.InterfaceUpdate, Name: "ens1", ID: 2}},
+		},
+		{
+			name:    "same name twice",
+			desired: []netbox.InterfaceEdit{edit("eth0", ""), edit("eth0", "")},
+			want:    []netbox.InterfaceChange{{Action: netbox.InterfaceCreate, Name: "eth0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := netbox.PlanInterfaceSync(current, tt.desired, netbox.InterfaceSyncOptions{MatchMAC: true})
+			if err != nil {
+				t.Fatalf("netbox.PlanInterfaceSync() error = %v", err)
+			}
+			if len(changes) != len(tt.want) {
+				t.Fatalf("netbox.PlanInterfaceSync() = %+v, want %+v", changes, tt.want)
+			}
+			for i, change := range changes {
+				want := tt.want[i]
+				if change.Action != want.Action || change.Name != want.Name || change.ID != want.ID {
+					t.Errorf("change %d = %s %s %d, want %s %s %d", i, change.Action, change.Name, change.ID, want.Action, want.Name, want.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanInterfaceSyncDeleteOrder(t *testing.T) {
+	current := []netbox.Interface{
+		{ID: 1, Name: "bond0"},
+		{ID: 2, Name: "eth0"},
+		{ID: 3, Name: "eth0.100", Parent: &netbox.NestedInterface{ID: 2, Name: "eth0"}},
+		{ID: 4, Name: "eth1", Lag: &netbox.NestedInterface{ID: 1, Name: "bond0"}},
+		{ID: 5, Name: "eth1.200", Parent: &netbox.NestedInterface{ID: 4, Name: "eth1"}},
+	}
+	keep := netbox.InterfaceEdit{}
+	keep.SetName("eth9")
+	changes, err := netbox.PlanInterfaceSync(current, []netbox.InterfaceEdit{keep}, netbox.InterfaceSyncOptions{Stale: netbox.DeleteStaleInterfaces})
+	if err != nil {
+		t.Fatalf("PlanInterfaceSync() error = %v", err)
+	}
+	var deleted []string
+	for _, change := range changes {
+		if change.Action == netbox.InterfaceDelete {
+			deleted = append(deleted, change.Name)
+		}
+	}
+	want := []string{"eth1.200", "eth0.100", "eth1", "bond0", "eth0"}
+	if fmt.Sprint(deleted) != fmt.Sprint(want) {
+		t.Errorf("PlanInterfaceSync() deletes %v, want %v", deleted, want)
+	}
+}
+
+func TestDiffInterfaceClearsTaggedVlans(t *testing.T) {
+	intf := netbox.Interface{ID: 1, Name: "eth0", TaggedVlans: []netbox.NestedVLAN{{ID: 10}}}
+	want := netbox.InterfaceEdit{}
+	want.SetName("eth0")
+	want.SetTaggedVlans([]int{})
+	edit, changed := netbox.DiffInterface(intf, want)
+	if !changed || edit.TaggedVlans == nil || len(edit.TaggedVlans) != 0 {
+		t.Errorf("netbox.DiffInterface() = %+v, %v, want tagged VLANs cleared", edit.TaggedVlans, changed)
+	}
+	intf.TaggedVlans = nil
+	if _, changed := netbox.DiffInterface(intf, want); changed {
+		t.Errorf("netbox.DiffInterface() without tagged VLANs changed")
+	}
+}
+
+func TestSyncInterfaces(t *testing.T) {
+	srv, c := newClient(t)
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	dev := srv.Add("/dcim/devices", netboxtest.Object{"name": "sw01", "site": site["id"]})
+	devID := int64(dev["id"].(int))
+	add := func(name string, mac string) netboxtest.Object {
+		return srv.Add("/dcim/interfaces", netboxtest.Object{
+			"device": dev["id"], "name": name, "type": "1000base-t", "mac_address": mac, "enabled": true,
+		})
+	}
+	eth0 := add("eth0", "00:00:00:00:00:aa")
+	renamed := add("eth1", "00:00:00:00:00:bb")
+	old := add("old0", "")
+
+	edit := func(name string, set func(*netbox.InterfaceEdit)) netbox.InterfaceEdit {
+		e := netbox.InterfaceEdit{}
+		e.SetName(name)
+		ifType := "1000base-t"
+		e.Type = &ifType
+		if set != nil {
+			set(&e)
+		}
+		return e
+	}
+	desired := []netbox.InterfaceEdit{
+		edit("eth0", func(e *netbox.InterfaceEdit) { e.SetSpeed(1000000) }),
+		edit("ens1", func(e *netbox.InterfaceEdit) { e.SetMac("00:00:00:00:00:BB") }),
+		edit("eth2", func(e *netbox.InterfaceEdit) { e.LagName = "bond0" }),
+		edit("bond0", func(e *netbox.InterfaceEdit) { lag := "lag"; e.Type = &lag }),
+		edit("eth0.10", func(e *netbox.InterfaceEdit) { e.ParentName = "eth0"; virtual := "virtual"; e.Type = &virtual }),
+	}
+	opts := netbox.InterfaceSyncOptions{MatchMAC: true, Stale: netbox.DisableStaleInterfaces}
+	changes, err := c.SyncInterfaces("device", devID, desired, opts)
+	if err != nil {
+		t.Fatalf("SyncInterfaces() error = %v", err)
+	}
+	if len(changes) != 6 {
+		t.Errorf("SyncInterfaces() made %d changes, want 6: %+v", len(changes), changes)
+	}
+
+	intfs, err := c.GetInterfacesForObject("device", devID)
+	if err != nil {
+		t.Fatalf("GetInterfacesForObject() error = %v", err)
+	}
+	byName := make(map[string]netbox.Interface)
+	for _, intf := range intfs {
+		byName[intf.Name] = intf
+	}
+	if intf := byName["eth0"]; intf.ID != eth0["id"] || intf.GetSpeed() != 1000000 {
+		t.Errorf("eth0 = %d speed %d", intf.ID, intf.GetSpeed())
+	}
+	if intf, ok := byName["ens1"]; !ok || intf.ID != renamed["id"] {
+		t.Errorf("ens1 = %+v, want eth1 renamed", intf)
+	}
+	if intf := byName["eth2"]; intf.Lag == nil || intf.Lag.Name != "bond0" {
+		t.Errorf("eth2 lag = %+v, want bond0", intf.Lag)
+	}
+	if intf := byName["eth0.10"]; intf.Parent == nil || intf.Parent.ID != eth0["id"] {
+		t.Errorf("eth0.10 parent = %+v, want eth0", intf.Parent)
+	}
+	if intf := byName["old0"]; intf.ID != old["id"] || intf.Enabled {
+		t.Errorf("old0 = %d enabled %v, want disabled", intf.ID, intf.Enabled)
+	}
+
+	changes, err = c.SyncInterfaces("device", devID, desired, opts)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("SyncInterfaces() second run = %+v, %v, want no changes", changes, err)
+	}
+	changes, err = c.SyncInterfaces("device", devID, desired, netbox.InterfaceSyncOptions{DryRun: true, Stale: netbox.DeleteStaleInterfaces})
+	if err != nil || len(changes) != 1 || changes[0].Action != netbox.InterfaceDelete || changes[0].ID != old["id"] {
+		t.Errorf("SyncInterfaces(dry run) = %+v, %v, want old0 deleted", changes, err)
+	}
+}
+
+func TestDiffInterfaceDescription(t *testing.T) {
+	intf := netbox.Interface{ID: 1, Name: "eth0", Description: "uplink"}
+	want := netbox.InterfaceEdit{}
+	want.SetName("eth0")
+	if _, changed := netbox.DiffInterface(intf, want); changed {
+		t.Errorf("DiffInterface() without a description changed")
+	}
+	want.SetDescription("uplink")
+	if _, changed := netbox.DiffInterface(intf, want); changed {
+		t.Errorf("DiffInterface() with the same description changed")
+	}
+	want.SetDescription("")
+	edit, changed := netbox.DiffInterface(intf, want)
+	data, _ := json.Marshal(edit)
+	if !changed || string(data) != `{"description":""}` {
+		t.Errorf("DiffInterface() = %s, %v, want the description cleared", data, changed)
+	}
+}
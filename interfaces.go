@@ -1,6 +1,7 @@
 package netbox
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -8,7 +9,12 @@ import (
 
 // FindInterfaceByName searches Netbox for the given interface name on the requested device
 func (c *Client) FindInterfaceByName(netboxType string, netboxDevice int64, ifName string) (intf Interface, err error) {
-	intfs, err := c.searchInterfaces(netboxType, netboxDevice, fmt.Sprintf("name=%s", url.QueryEscape(ifName)))
+	return c.FindInterfaceByNameCtx(context.Background(), netboxType, netboxDevice, ifName)
+}
+
+// FindInterfaceByNameCtx is like FindInterfaceByName but uses ctx for the requests.
+func (c *Client) FindInterfaceByNameCtx(ctx context.Context, netboxType string, netboxDevice int64, ifName string) (intf Interface, err error) {
+	intfs, err := c.searchInterfaces(ctx, netboxType, netboxDevice, fmt.Sprintf("name=%s", url.QueryEscape(ifName)))
 	if err != nil {
 		return intf, err
 	}
@@ -20,11 +26,15 @@ func (c *Client) FindInterfaceByName(netboxType string, netboxDevice int64, ifNa
 
 // GetInterfacesforDevices returns all interfaces for the given device.
 func (c *Client) GetInterfacesForObject(netboxType string, netboxDevice int64) (intfs []Interface, err error) {
-	return c.searchInterfaces(netboxType, netboxDevice)
+	return c.GetInterfacesForObjectCtx(context.Background(), netboxType, netboxDevice)
+}
+
+// GetInterfacesForObjectCtx is like GetInterfacesForObject but uses ctx for the requests.
+func (c *Client) GetInterfacesForObjectCtx(ctx context.Context, netboxType string, netboxDevice int64) (intfs []Interface, err error) {
+	return c.searchInterfaces(ctx, netboxType, netboxDevice)
 }
 
-func (c *Client) searchInterfaces(netboxType string, netboxDevice int64, args ...string) (intfs []Interface, err error) {
-	var url *string
+func (c *Client) searchInterfaces(ctx context.Context, netboxType string, netboxDevice int64, args ...string) (intfs []Interface, err error) {
 	id := "device_id"
 
 	model, err := getInterfaceType(netboxType)
@@ -34,29 +44,8 @@ func (c *Client) searchInterfaces(netboxType string, netboxDevice int64, args ..
 	if netboxType == "virtualmachine" {
 		id = "virtual_machine_id"
 	}
-	obj := &InterfacesResponse{}
-	r := c.buildRequest().SetResult(obj)
-	path := GetPathForModel(model) + "/?" + id + "=%d"
-	for _, arg := range args {
-		path = fmt.Sprintf("%s&%s", path, arg)
-	}
-	path = c.buildURL(path, netboxDevice)
-	url = &path
-	for url != nil {
-		resp, err := r.Get(*url)
-		if err != nil {
-			c.log.Error(fmt.Sprintf("error searching %s", r.URL), "err", err)
-			return intfs, err
-		}
-		if resp.IsError() {
-			c.log.Error(fmt.Sprintf("%d searching %s", resp.StatusCode(), r.URL), "err", err)
-			return intfs, fmt.Errorf("%s: %s", resp.Error(), resp.Body())
-		}
-		intfs = append(intfs, obj.Results...)
-		url = obj.Next
-	}
-	return intfs, nil
-
+	args = append([]string{fmt.Sprintf("%s=%d", id, netboxDevice)}, args...)
+	return listAll[Interface](ctx, c, model, args...)
 }
 
 func getInterfaceType(netboxType string) (string, error) {
@@ -67,18 +56,37 @@ func getInterfaceType(netboxType string) (string, error) {
 	return model[netboxType], nil
 }
 
-// AddInterface will create a new interface on the given device
+// AddInterface will create a new interface on the given device.  For a
+// netboxType of "virtualmachine" the interface is owned by the
+// virtual_machine, otherwise by the device, and the other is not sent.
 func (c *Client) AddInterface(netboxType string, netboxDevice int64, intf InterfaceEdit) (Interface, error) {
+	return c.AddInterfaceCtx(context.Background(), netboxType, netboxDevice, intf)
+}
+
+// AddInterfaceCtx is like AddInterface but uses ctx for the request.
+func (c *Client) AddInterfaceCtx(ctx context.Context, netboxType string, netboxDevice int64, intf InterfaceEdit) (Interface, error) {
 	devid := int(netboxDevice)
 	newIntf := Interface{}
-	intf.Device = &devid
 	ifType, err := getInterfaceType(netboxType)
 	if err != nil {
 		return newIntf, err
 	}
-	r := c.buildRequest().SetResult(&newIntf).SetBody(intf)
+	// Netbox rejects a VM interface posted with a device
+	if netboxType == "virtualmachine" {
+		intf.VM, intf.Device = &devid, nil
+	} else {
+		intf.Device, intf.VM = &devid, nil
+	}
+	if err = c.resolveInterfaceNames(ctx, netboxType, netboxDevice, &intf); err != nil {
+		return newIntf, err
+	}
+	url, err := c.modelURL(OpCreate, ifType)
+	if err != nil {
+		return newIntf, err
+	}
+	r := c.buildRequest(ctx).SetResult(&newIntf).SetBody(intf)
 
-	resp, err := r.Post(c.buildURL(GetPathForModel(ifType) + "/"))
+	resp, err := r.Post(url)
 	if err != nil {
 		c.log.Error("error adding interface", "device", netboxDevice, "interface", intf.Name, "error", err)
 		return newIntf, err
@@ -93,12 +101,34 @@ func (c *Client) AddInterface(netboxType string, netboxDevice int64, intf Interf
 
 // UpdateInterface modifies the values of the given interface in Netbox
 func (c *Client) UpdateInterface(netboxType string, intfID int64, intf InterfaceEdit) error {
+	return c.UpdateInterfaceCtx(context.Background(), netboxType, intfID, intf)
+}
+
+// UpdateInterfaceCtx is like UpdateInterface but uses ctx for the request.
+func (c *Client) UpdateInterfaceCtx(ctx context.Context, netboxType string, intfID int64, intf InterfaceEdit) error {
 	ifType, err := getInterfaceType(netboxType)
 	if err != nil {
 		return err
 	}
-	r := c.buildRequest().SetBody(intf)
-	resp, err := r.Patch(c.buildURL(GetPathForModel(ifType)+"/%d/", intfID))
+	if (intf.Parent == nil && intf.ParentName != "") || (intf.Lag == nil && intf.LagName != "") {
+		current, err := getObject[Interface](ctx, c, ifType, int(intfID))
+		if err != nil {
+			return err
+		}
+		owner := current.Device.ID
+		if current.VirtualMachine != nil {
+			owner = current.VirtualMachine.ID
+		}
+		if err = c.resolveInterfaceNames(ctx, netboxType, int64(owner), &intf); err != nil {
+			return err
+		}
+	}
+	url, err := c.modelURL(OpUpdate, ifType, int(intfID))
+	if err != nil {
+		return err
+	}
+	r := c.buildRequest(ctx).SetBody(intf)
+	resp, err := r.Patch(url)
 	if err != nil {
 		c.log.Error("error updating interface", "interface", intfID, "error", err)
 		return err
@@ -110,3 +140,42 @@ func (c *Client) UpdateInterface(netboxType string, intfID int64, intf Interface
 	c.log.Info("update interface", "interface", intfID, "status", resp.Status(), "url", r.URL)
 	return nil
 }
+
+// DeleteInterface removes the interface from Netbox
+func (c *Client) DeleteInterface(netboxType string, intfID int64) error {
+	return c.DeleteInterfaceCtx(context.Background(), netboxType, intfID)
+}
+
+// DeleteInterfaceCtx is like DeleteInterface but uses ctx for the request.
+func (c *Client) DeleteInterfaceCtx(ctx context.Context, netboxType string, intfID int64) error {
+	ifType, err := getInterfaceType(netboxType)
+	if err != nil {
+		return err
+	}
+	if err = c.deleteObject(ctx, ifType, int(intfID)); err != nil {
+		c.log.Error("error deleting interface", "interface", intfID, "error", err)
+		return err
+	}
+	c.log.Info("delete interface", "interface", intfID)
+	return nil
+}
+
+// resolveInterfaceNames sets the parent and LAG of intf from ParentName
+// and LagName, looked up among the interfaces of the device
+func (c *Client) resolveInterfaceNames(ctx context.Context, netboxType string, netboxDevice int64, intf *InterfaceEdit) error {
+	if intf.Parent == nil && intf.ParentName != "" {
+		parent, err := c.FindInterfaceByNameCtx(ctx, netboxType, netboxDevice, intf.ParentName)
+		if err != nil {
+			return fmt.Errorf("parent %s: %w", intf.ParentName, err)
+		}
+		intf.SetParent(parent.ID)
+	}
+	if intf.Lag == nil && intf.LagName != "" {
+		lag, err := c.FindInterfaceByNameCtx(ctx, netboxType, netboxDevice, intf.LagName)
+		if err != nil {
+			return fmt.Errorf("lag %s: %w", intf.LagName, err)
+		}
+		intf.SetLag(lag.ID)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package netbox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestAddInterfaceOwner(t *testing.T) {
+	srv, c := newClient(t)
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	dev := srv.Add("/dcim/devices", netboxtest.Object{"name": "sw01", "site": site["id"]})
+	vm := srv.Add("/virtualization/virtual-machines", netboxtest.Object{"name": "vm01"})
+	tests := []struct {
+		netboxType string
+		id         int
+		path       string
+		field      string
+		absent     string
+	}{
+		{"device", dev["id"].(int), "/dcim/interfaces", "device", "virtual_machine"},
+		{"virtualmachine", vm["id"].(int), "/virtualization/interfaces", "virtual_machine", "device"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.netboxType, func(t *testing.T) {
+			// an owner of the wrong kind is replaced
+			other := 99
+			edit := netbox.InterfaceEdit{Name: strPtr("eth0"), Device: &other, VM: &other}
+			if tt.netboxType == "device" {
+				edit.Type = strPtr("1000base-t")
+			}
+			intf, err := c.AddInterface(tt.netboxType, int64(tt.id), edit)
+			if err != nil {
+				t.Fatalf("AddInterface() error = %v", err)
+			}
+			stored, ok := srv.Get(tt.path, intf.ID)
+			if !ok {
+				t.Fatalf("interface %d not stored at %s", intf.ID, tt.path)
+			}
+			owner, _ := stored[tt.field].(netboxtest.Object)
+			if owner["id"] != tt.id {
+				t.Errorf("%s = %v, want %d", tt.field, stored[tt.field], tt.id)
+			}
+			if _, ok := stored[tt.absent]; ok {
+				t.Errorf("interface has %s: %v", tt.absent, stored)
+			}
+		})
+	}
+}
+
+func TestInterfaceFields(t *testing.T) {
+	srv, c := newClient(t)
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	dev := srv.Add("/dcim/devices", netboxtest.Object{"name": "sw01", "site": site["id"]})
+	devID := int64(dev["id"].(int))
+	vrf, err := c.GetOrAddVRF("mgmt", "")
+	if err != nil {
+		t.Fatalf("GetOrAddVRF() error = %v", err)
+	}
+	lagType, physical := "lag", "1000base-t"
+	if _, err := c.AddInterface("device", devID, netbox.InterfaceEdit{Name: strPtr("bond0"), Type: &lagType}); err != nil {
+		t.Fatalf("AddInterface(bond0) error = %v", err)
+	}
+
+	edit := netbox.InterfaceEdit{Name: strPtr("eth0"), Type: &physical, LagName: "bond0"}
+	for _, changed := range []bool{
+		edit.SetEnabled(false),
+		edit.SetMtu(9000),
+		edit.SetMgmtOnly(true),
+		edit.SetVrf(vrf.ID),
+		edit.SetTags([]netbox.Tag{{Name: "Uplink", Slug: "uplink"}}),
+		edit.SetCustomField("circuit", "C-1"),
+	} {
+		if !changed {
+			t.Errorf("setter reported no change")
+		}
+	}
+	if edit.SetMtu(0) || edit.SetVrf(0) || edit.SetLag(0) {
+		t.Errorf("setters with zero values reported a change")
+	}
+	intf, err := c.AddInterface("device", devID, edit)
+	if err != nil {
+		t.Fatalf("AddInterface(eth0) error = %v", err)
+	}
+	if intf.Enabled || intf.Mtu == nil || *intf.Mtu != 9000 || !intf.MgmtOnly {
+		t.Errorf("AddInterface() enabled %v mtu %v mgmt_only %v", intf.Enabled, intf.Mtu, intf.MgmtOnly)
+	}
+	if intf.Vrf == nil || intf.Vrf.Name != "mgmt" || intf.Lag == nil || intf.Lag.Name != "bond0" {
+		t.Errorf("AddInterface() vrf %+v lag %+v", intf.Vrf, intf.Lag)
+	}
+	if len(intf.Tags) != 1 || intf.Tags[0].Slug != "uplink" || intf.CustomFields["circuit"] != "C-1" {
+		t.Errorf("AddInterface() tags %+v custom fields %v", intf.Tags, intf.CustomFields)
+	}
+
+	if _, err := c.AddInterface("device", devID, netbox.InterfaceEdit{Name: strPtr("bond1"), Type: &lagType}); err != nil {
+		t.Fatalf("AddInterface(bond1) error = %v", err)
+	}
+	if err := c.UpdateInterface("device", int64(intf.ID), netbox.InterfaceEdit{LagName: "bond1"}); err != nil {
+		t.Fatalf("UpdateInterface() error = %v", err)
+	}
+	updated, _ := c.FindInterfaceByName("device", devID, "eth0")
+	if updated.Lag == nil || updated.Lag.Name != "bond1" {
+		t.Errorf("UpdateInterface() lag = %+v, want bond1", updated.Lag)
+	}
+	if err := c.UpdateInterface("device", int64(intf.ID), netbox.InterfaceEdit{LagName: "bond9"}); !errors.Is(err, netbox.ErrNotFound) {
+		t.Errorf("UpdateInterface() with an unknown lag error = %v, want %v", err, netbox.ErrNotFound)
+	}
+
+	if err := c.DeleteInterface("device", int64(intf.ID)); err != nil {
+		t.Fatalf("DeleteInterface() error = %v", err)
+	}
+	if _, err := c.FindInterfaceByName("device", devID, "eth0"); !errors.Is(err, netbox.ErrNotFound) {
+		t.Errorf("FindInterfaceByName() after delete error = %v, want %v", err, netbox.ErrNotFound)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -1,76 +1,440 @@
 package netbox
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/netip"
+	"net/url"
+	"sort"
 )
 
+// Address families
 const (
-	ipPath = "/ipam/ip-addresses/"
+	IPv4 = 4
+	IPv6 = 6
 )
 
-// SearchIP searches for the given IP as an ipaddress.
+// AddressFamily is the IP version of an address or prefix.  Netbox
+// renders it as {"value": 4, "label": "IPv4"}, or as a bare number inside
+// nested objects; both forms decode.
+type AddressFamily struct {
+	Label string `json:"label"`
+	Value int    `json:"value"`
+}
+
+func (f *AddressFamily) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value int
+	if err := json.Unmarshal(data, &value); err == nil {
+		f.Value = value
+		f.Label = fmt.Sprintf("IPv%d", value)
+		return nil
+	}
+	type family AddressFamily
+	return json.Unmarshal(data, (*family)(f))
+}
+
+// Is4 reports whether the family is IPv4
+func (f AddressFamily) Is4() bool {
+	return f.Value == IPv4
+}
+
+// Is6 reports whether the family is IPv6
+func (f AddressFamily) Is6() bool {
+	return f.Value == IPv6
+}
+
+// Prefix parses the address and its mask
+func (ip IP) Prefix() (netip.Prefix, error) {
+	return netip.ParsePrefix(ip.Address)
+}
+
+// Addr parses the address without its mask
+func (ip IP) Addr() (netip.Addr, error) {
+	prefix, err := ip.Prefix()
+	return prefix.Addr(), err
+}
+
+// Prefix parses the address and its mask
+func (p PrimaryI) Prefix() (netip.Prefix, error) {
+	return netip.ParsePrefix(p.Address)
+}
+
+// Addr parses the address without its mask
+func (p PrimaryI) Addr() (netip.Addr, error) {
+	prefix, err := p.Prefix()
+	return prefix.Addr(), err
+}
+
+// IPQuery selects ipaddress records.  Zero values are not used as
+// filters.
+type IPQuery struct {
+	// Addr matches the host address with any mask length.
+	Addr netip.Addr
+	// Prefix matches the address and mask exactly.  It is ignored when
+	// Addr is set.
+	Prefix netip.Prefix
+	// VrfID limits the search to the given VRF.  nil matches every VRF
+	// and 0 matches addresses in the global table only.
+	VrfID *int
+	// TenantID limits the search to addresses of the tenant.
+	TenantID int
+	// Parent limits the search to addresses within the prefix.
+	Parent netip.Prefix
+	// Status limits the search to addresses with the status, eg. "active".
+	Status string
+}
+
+func (q IPQuery) args() []string {
+	var args []string
+	switch {
+	case q.Addr.IsValid():
+		args = append(args, "address="+url.QueryEscape(q.Addr.String()))
+	case q.Prefix.IsValid():
+		args = append(args, "address="+url.QueryEscape(q.Prefix.String()))
+	}
+	if q.VrfID != nil {
+		args = append(args, vrfArg(*q.VrfID))
+	}
+	if q.TenantID != 0 {
+		args = append(args, fmt.Sprintf("tenant_id=%d", q.TenantID))
+	}
+	if q.Parent.IsValid() {
+		args = append(args, "parent="+url.QueryEscape(q.Parent.Masked().String()))
+	}
+	if q.Status != "" {
+		args = append(args, "status="+url.QueryEscape(q.Status))
+	}
+	return args
+}
+
+// vrfArg returns the vrf_id filter for the VRF, where 0 selects the
+// global table
+func vrfArg(vrfID int) string {
+	if vrfID == 0 {
+		return "vrf_id=null"
+	}
+	return fmt.Sprintf("vrf_id=%d", vrfID)
+}
+
+// SearchIP searches for the given IP as an ipaddress.  The IP may be
+// given with or without a mask; without one every mask length matches.
 func (c *Client) SearchIP(ip string) (*IPSearchResults, error) {
+	return c.SearchIPCtx(context.Background(), ip)
+}
+
+// SearchIPCtx is like SearchIP but uses ctx for the requests.
+func (c *Client) SearchIPCtx(ctx context.Context, ip string) (*IPSearchResults, error) {
 	obj := &IPSearchResults{}
-	r := c.buildRequest().SetResult(obj)
-	url := fmt.Sprintf("%s?address=%s", c.buildURL(ipPath), ip)
-	resp, err := r.Get(url)
+	results, err := listAll[IP](ctx, c, "ipaddress", "address="+url.QueryEscape(ip))
 	if err != nil {
 		c.log.Error("Could not find address", "err", err)
 		return obj, err
 	}
-	if err = checkStatus(resp); err != nil {
-		c.log.Error("Error returned by netbox", "err", err)
-		return obj, err
+	obj.Count = len(results)
+	obj.Results = results
+	return obj, nil
+}
+
+// SearchIPs returns every ipaddress matching the query
+func (c *Client) SearchIPs(query IPQuery) ([]IP, error) {
+	return c.SearchIPsCtx(context.Background(), query)
+}
+
+// SearchIPsCtx is like SearchIPs but uses ctx for the requests.
+func (c *Client) SearchIPsCtx(ctx context.Context, query IPQuery) ([]IP, error) {
+	results, err := listAll[IP](ctx, c, "ipaddress", query.args()...)
+	if err != nil {
+		c.log.Error("Could not find address", "query", query.args(), "err", err)
 	}
-	return obj, err
+	return results, err
+}
+
+// FindIPExact returns the only ipaddress matching the query, which must
+// set Addr or Prefix.  ErrNotFound or ErrMultipleResults is returned
+// otherwise.
+func (c *Client) FindIPExact(query IPQuery) (IP, error) {
+	return c.FindIPExactCtx(context.Background(), query)
+}
+
+// FindIPExactCtx is like FindIPExact but uses ctx for the requests.
+func (c *Client) FindIPExactCtx(ctx context.Context, query IPQuery) (IP, error) {
+	if !query.Addr.IsValid() && !query.Prefix.IsValid() {
+		return IP{}, errors.New("an address or prefix is required")
+	}
+	results, err := c.SearchIPsCtx(ctx, query)
+	if err != nil {
+		return IP{}, err
+	}
+	switch len(results) {
+	case 0:
+		return IP{}, ErrNotFound
+	case 1:
+		return results[0], nil
+	}
+	return IP{}, fmt.Errorf("%w: %d", ErrMultipleResults, len(results))
+}
+
+// DNSPolicy controls which ipaddress records SetIPDNSWithPolicy changes.
+type DNSPolicy struct {
+	// Overwrite replaces a dns_name that differs from the requested FQDN.
+	// Otherwise only records without a dns_name are updated.
+	Overwrite bool
+	// VrfID limits the update to addresses in the given VRF.  nil matches
+	// every VRF and 0 matches addresses in the global table only.
+	VrfID *int
+	// Journal writes an info journal entry on every address changed.
+	Journal bool
+}
+
+// DNSResult lists the ipaddress IDs SetIPDNSWithPolicy touched.
+type DNSResult struct {
+	Updated []int
+	Skipped []int
+	Failed  map[int]error
+}
+
+// Err returns the errors of all failed updates joined in ID order, or nil.
+func (r DNSResult) Err() error {
+	ids := make([]int, 0, len(r.Failed))
+	for id := range r.Failed {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	var errs []error
+	for _, id := range ids {
+		errs = append(errs, fmt.Errorf("ipaddress %d: %w", id, r.Failed[id]))
+	}
+	return errors.Join(errs...)
 }
 
 // SetIPDNS searches for the IP given and updates the DNS address
 // with the provided FQDN.  It updates all matching ipaddress
 // records where the dnsname is not already set.
 func (c *Client) SetIPDNS(ip string, dns string) error {
-	obj, err := c.SearchIP(ip)
+	return c.SetIPDNSCtx(context.Background(), ip, dns)
+}
+
+// SetIPDNSCtx is like SetIPDNS but uses ctx for the requests.
+func (c *Client) SetIPDNSCtx(ctx context.Context, ip string, dns string) error {
+	result, err := c.SetIPDNSWithPolicyCtx(ctx, ip, dns, DNSPolicy{})
 	if err != nil {
-		c.log.Error("Could not find address", "err", err)
 		return err
 	}
-	for _, addr := range obj.Results {
-		if addr.DNSName == "" {
-			c.UpdateAddress(addr.URL, dns)
+	return result.Err()
+}
+
+// SetIPDNSWithPolicy updates the dns_name of every ipaddress record
+// matching ip as allowed by policy and reports what happened to each.
+// An error is only returned if the addresses could not be searched;
+// failed updates are listed in the result.
+func (c *Client) SetIPDNSWithPolicy(ip string, dns string, policy DNSPolicy) (DNSResult, error) {
+	return c.SetIPDNSWithPolicyCtx(context.Background(), ip, dns, policy)
+}
+
+// SetIPDNSWithPolicyCtx is like SetIPDNSWithPolicy but uses ctx for the requests.
+func (c *Client) SetIPDNSWithPolicyCtx(ctx context.Context, ip string, dns string, policy DNSPolicy) (DNSResult, error) {
+	result := DNSResult{Failed: make(map[int]error)}
+	args := []string{"address=" + url.QueryEscape(ip)}
+	if policy.VrfID != nil {
+		args = append(args, vrfArg(*policy.VrfID))
+	}
+	addrs, err := listAll[IP](ctx, c, "ipaddress", args...)
+	if err != nil {
+		c.log.Error("Could not find address", "err", err)
+		return result, err
+	}
+	for _, addr := range addrs {
+		if addr.DNSName == dns || (addr.DNSName != "" && !policy.Overwrite) {
+			result.Skipped = append(result.Skipped, addr.ID)
+			continue
+		}
+		if err := c.UpdateAddressCtx(ctx, addr.URL, dns); err != nil {
+			result.Failed[addr.ID] = err
+			continue
+		}
+		result.Updated = append(result.Updated, addr.ID)
+		if policy.Journal {
+			comment := fmt.Sprintf("DNS name set to %s", dns)
+			if addr.DNSName != "" {
+				comment = fmt.Sprintf("DNS name changed from %s to %s", addr.DNSName, dns)
+			}
+			if err := c.AddJournalEntryCtx(ctx, "ipaddress", int64(addr.ID), InfoLevel, "%s", comment); err != nil {
+				c.log.Warn("could not add journal entry", "address", addr.ID, "error", err)
+			}
 		}
 	}
-	return err
+	return result, nil
 }
 
 // UpdateAddress updates the ipaddress indicated the by the URL
 // with the given dns FQDN
-func (c *Client) UpdateAddress(url, dns string) {
+func (c *Client) UpdateAddress(url, dns string) error {
+	return c.UpdateAddressCtx(context.Background(), url, dns)
+}
+
+// UpdateAddressCtx is like UpdateAddress but uses ctx for the request.
+func (c *Client) UpdateAddressCtx(ctx context.Context, url, dns string) error {
 	data := make(map[string]interface{})
 	data["dns_name"] = dns
 	obj := make(map[string]interface{})
-	r := c.buildRequest().SetResult(&obj)
+	r := c.buildRequest(ctx).SetResult(&obj)
 	r.SetBody(data)
 	resp, err := r.Patch(url)
 	if err != nil {
 		c.log.Error("Could not update DNS", "url", url, "err", err)
+		return err
 	}
 	if err = checkStatus(resp); err != nil {
 		c.log.Error("Error returned by netbox", "url", url, "err", err)
+		return err
+	}
+	return nil
+}
+
+// IPEdit is used to add/update an ipaddress
+type IPEdit struct {
+	Address            string                 `json:"address,omitempty"`
+	AssignedObjectType *string                `json:"assigned_object_type,omitempty"`
+	AssignedObjectID   *int                   `json:"assigned_object_id,omitempty"`
+	Vrf                *int                   `json:"vrf,omitempty"`
+	Tenant             *int                   `json:"tenant,omitempty"`
+	Status             *string                `json:"status,omitempty"`
+	Role               *string                `json:"role,omitempty"`
+	DNSName            *string                `json:"dns_name,omitempty"`
+	Description        *string                `json:"description,omitempty"`
+	Comments           *string                `json:"comments,omitempty"`
+	Tags               []Tag                  `json:"tags,omitempty"`
+	CustomFields       map[string]interface{} `json:"custom_fields,omitempty"`
+	// SetPrimary makes the address the primary_ip4 or primary_ip6 of the
+	// device or virtualmachine owning the assigned interface.  It is not
+	// sent to Netbox.
+	SetPrimary bool `json:"-"`
+}
+
+// SetAddress sets the address and mask
+func (e *IPEdit) SetAddress(prefix netip.Prefix) {
+	e.Address = prefix.String()
+}
+
+// AssignInterface assigns the address to an interface of a device or
+// virtualmachine, as given by netboxType
+func (e *IPEdit) AssignInterface(netboxType string, ifID int) error {
+	ifType, err := getInterfaceType(netboxType)
+	if err != nil {
+		return err
 	}
+	m, err := LookupModel(ifType)
+	if err != nil {
+		return err
+	}
+	e.AssignedObjectType = &m.ContentType
+	e.AssignedObjectID = &ifID
+	return nil
 }
 
 // AddIP adds an IP address to Netbox
 func (c *Client) AddIP(ipaddress string) (IP, error) {
-	data := make(map[string]interface{})
-	data["address"] = ipaddress
-	obj := IP{}
-	r := c.buildRequest().SetResult(&obj)
-	r.SetBody(data)
-	resp, err := r.Post(c.buildURL(ipPath))
+	return c.AddIPCtx(context.Background(), ipaddress)
+}
+
+// AddIPCtx is like AddIP but uses ctx for the request.
+func (c *Client) AddIPCtx(ctx context.Context, ipaddress string) (IP, error) {
+	return c.CreateIPCtx(ctx, IPEdit{Address: ipaddress})
+}
+
+// CreateIP adds an IP address with the given fields to Netbox.  When
+// ip.SetPrimary is set the address also becomes the primary address of
+// its parent device or virtualmachine.
+func (c *Client) CreateIP(ip IPEdit) (IP, error) {
+	return c.CreateIPCtx(context.Background(), ip)
+}
+
+// CreateIPCtx is like CreateIP but uses ctx for the requests.
+func (c *Client) CreateIPCtx(ctx context.Context, ip IPEdit) (IP, error) {
+	obj, err := createObject[IP](ctx, c, "ipaddress", ip)
 	if err != nil {
 		return obj, err
 	}
-	err = checkStatus(resp)
+	if ip.SetPrimary {
+		err = c.setPrimaryForAssigned(ctx, obj)
+	}
+	return obj, err
+}
 
+// UpdateIP modifies the given fields of the ipaddress.  When
+// ip.SetPrimary is set the address also becomes the primary address of
+// its parent device or virtualmachine.
+func (c *Client) UpdateIP(id int, ip IPEdit) (IP, error) {
+	return c.UpdateIPCtx(context.Background(), id, ip)
+}
+
+// UpdateIPCtx is like UpdateIP but uses ctx for the requests.
+func (c *Client) UpdateIPCtx(ctx context.Context, id int, ip IPEdit) (IP, error) {
+	obj, err := updateObject[IP](ctx, c, "ipaddress", id, ip)
+	if err != nil {
+		return obj, err
+	}
+	if ip.SetPrimary {
+		err = c.setPrimaryForAssigned(ctx, obj)
+	}
 	return obj, err
 }
+
+// AssignIPToInterface assigns an existing ipaddress to an interface of a
+// device or virtualmachine, as given by netboxType
+func (c *Client) AssignIPToInterface(ipID int, netboxType string, ifID int) (IP, error) {
+	return c.AssignIPToInterfaceCtx(context.Background(), ipID, netboxType, ifID)
+}
+
+// AssignIPToInterfaceCtx is like AssignIPToInterface but uses ctx for the request.
+func (c *Client) AssignIPToInterfaceCtx(ctx context.Context, ipID int, netboxType string, ifID int) (IP, error) {
+	edit := IPEdit{}
+	if err := edit.AssignInterface(netboxType, ifID); err != nil {
+		return IP{}, err
+	}
+	return c.UpdateIPCtx(ctx, ipID, edit)
+}
+
+// SetPrimaryIP sets the address as the primary_ip4 or primary_ip6,
+// depending on its family, of the given device or virtualmachine
+func (c *Client) SetPrimaryIP(netboxType string, objectID int64, ip IP) error {
+	return c.SetPrimaryIPCtx(context.Background(), netboxType, objectID, ip)
+}
+
+// SetPrimaryIPCtx is like SetPrimaryIP but uses ctx for the request.
+func (c *Client) SetPrimaryIPCtx(ctx context.Context, netboxType string, objectID int64, ip IP) error {
+	if netboxType != "device" && netboxType != "virtualmachine" {
+		return errors.New("netboxType must be one of 'device' or 'virtualmachine'")
+	}
+	family := ip.Family.Value
+	if family == 0 {
+		addr, err := ip.Addr()
+		if err != nil {
+			return fmt.Errorf("could not determine the family of %s: %w", ip.Address, err)
+		}
+		family = IPv4
+		if addr.Is6() {
+			family = IPv6
+		}
+	}
+	field := fmt.Sprintf("primary_ip%d", family)
+	c.log.Info("set primary address", "type", netboxType, "id", objectID, "field", field, "address", ip.Address)
+	return c.UpdateObjectWithMapCtx(ctx, netboxType, objectID, map[string]interface{}{field: ip.ID})
+}
+
+// setPrimaryForAssigned makes ip the primary address of the device or
+// virtualmachine its assigned interface belongs to
+func (c *Client) setPrimaryForAssigned(ctx context.Context, ip IP) error {
+	switch {
+	case ip.AssignedObject.Device.ID != 0:
+		return c.SetPrimaryIPCtx(ctx, "device", int64(ip.AssignedObject.Device.ID), ip)
+	case ip.AssignedObject.VirtualMachine.ID != 0:
+		return c.SetPrimaryIPCtx(ctx, "virtualmachine", int64(ip.AssignedObject.VirtualMachine.ID), ip)
+	}
+	return fmt.Errorf("address %s is not assigned to an interface", ip.Address)
+}
@@ -0,0 +1,181 @@
+package netbox_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestIPfromCIDR(t *testing.T) {
+	tests := []struct {
+		name string
+		cidr string
+		want string
+	}{
+		{name: "IPv4 with mask", cidr: "10.0.0.1/24", want: "10.0.0.1"},
+		{name: "IPv4 without mask", cidr: "10.0.0.1", want: "10.0.0.1"},
+		{name: "IPv6 is canonical", cidr: "2001:DB8:0:0::1/64", want: "2001:db8::1"},
+		{name: "Not an address", cidr: "host/24", want: "host"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := netbox.IPfromCIDR(tt.cidr); got != tt.want {
+				t.Errorf("IPfromCIDR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressFamily(t *testing.T) {
+	var ip netbox.IP
+	if err := json.Unmarshal([]byte(`{"address": "2001:db8::1/64", "family": {"value": 6, "label": "IPv6"}}`), &ip); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !ip.Family.Is6() || ip.Family.Label != "IPv6" {
+		t.Errorf("IP family = %+v, want IPv6", ip.Family)
+	}
+	addr, err := ip.Addr()
+	if err != nil || addr.String() != "2001:db8::1" {
+		t.Errorf("Addr() = %v, %v", addr, err)
+	}
+
+	var primary netbox.PrimaryI
+	if err := json.Unmarshal([]byte(`{"address": "10.0.0.1/24", "family": 4}`), &primary); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !primary.Family.Is4() || primary.Family.Label != "IPv4" {
+		t.Errorf("PrimaryI family = %+v, want IPv4", primary.Family)
+	}
+	prefix, err := primary.Prefix()
+	if err != nil || prefix.Bits() != 24 {
+		t.Errorf("Prefix() = %v, %v", prefix, err)
+	}
+}
+
+func TestDNSResultErrOrder(t *testing.T) {
+	r := netbox.DNSResult{Failed: map[int]error{
+		30: errors.New("c"),
+		10: errors.New("a"),
+		20: errors.New("b"),
+	}}
+	want := "ipaddress 10: a\nipaddress 20: b\nipaddress 30: c"
+	for i := 0; i < 10; i++ {
+		if got := r.Err(); got == nil || got.Error() != want {
+			t.Fatalf("Err() = %v, want %q", got, want)
+		}
+	}
+	if err := (netbox.DNSResult{}).Err(); err != nil {
+		t.Errorf("Err() without failures = %v", err)
+	}
+}
+
+func TestCreateIPAssigned(t *testing.T) {
+	srv, c := newClient(t)
+	vm := srv.Add("/virtualization/virtual-machines", netboxtest.Object{"name": "web01"})
+	name := "eth0"
+	intf, err := c.AddInterface("virtualmachine", int64(vm["id"].(int)), netbox.InterfaceEdit{Name: &name})
+	if err != nil {
+		t.Fatalf("AddInterface() error = %v", err)
+	}
+	status := "active"
+	edit := netbox.IPEdit{Address: "2001:db8::10/64", Status: &status, SetPrimary: true}
+	if err := edit.AssignInterface("virtualmachine", intf.ID); err != nil {
+		t.Fatalf("AssignInterface() error = %v", err)
+	}
+	ip, err := c.CreateIP(edit)
+	if err != nil {
+		t.Fatalf("CreateIP() error = %v", err)
+	}
+	if ip.AssignedObjectType != "virtualization.vminterface" || ip.AssignedObject.VirtualMachine.ID != vm["id"] {
+		t.Errorf("CreateIP() assigned to %s %+v", ip.AssignedObjectType, ip.AssignedObject)
+	}
+	stored, _ := srv.Get("/virtualization/virtual-machines", vm["id"].(int))
+	if primary, _ := stored["primary_ip6"].(map[string]any); primary == nil || primary["id"] != ip.ID {
+		t.Errorf("primary_ip6 = %v, want address %d", stored["primary_ip6"], ip.ID)
+	}
+
+	other, err := c.AddIP("10.0.0.5/24")
+	if err != nil {
+		t.Fatalf("AddIP() error = %v", err)
+	}
+	moved, err := c.AssignIPToInterface(other.ID, "virtualmachine", intf.ID)
+	if err != nil {
+		t.Fatalf("AssignIPToInterface() error = %v", err)
+	}
+	if moved.AssignedObjectID != intf.ID {
+		t.Errorf("AssignIPToInterface() assigned_object_id = %d, want %d", moved.AssignedObjectID, intf.ID)
+	}
+	if _, err := c.AssignIPToInterface(other.ID, "rack", intf.ID); err == nil {
+		t.Errorf("AssignIPToInterface() with an invalid type returned no error")
+	}
+}
+
+func TestSetIPDNSWithPolicy(t *testing.T) {
+	srv, c := newClient(t)
+	empty := srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.1/24"})
+	stale := srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.1/32", "dns_name": "old.example.com"})
+	same := srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.1/16", "dns_name": "host.example.com"})
+
+	result, err := c.SetIPDNSWithPolicy("10.0.0.1", "host.example.com", netbox.DNSPolicy{})
+	if err != nil {
+		t.Fatalf("SetIPDNSWithPolicy() error = %v", err)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != empty["id"] || len(result.Skipped) != 2 || result.Err() != nil {
+		t.Errorf("SetIPDNSWithPolicy() = %+v", result)
+	}
+
+	global := 0
+	result, err = c.SetIPDNSWithPolicy("10.0.0.1", "host.example.com", netbox.DNSPolicy{Overwrite: true, VrfID: &global, Journal: true})
+	if err != nil {
+		t.Fatalf("SetIPDNSWithPolicy() error = %v", err)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != stale["id"] {
+		t.Errorf("SetIPDNSWithPolicy() with overwrite = %+v, want %v updated", result, stale["id"])
+	}
+	if stored, _ := srv.Get("/ipam/ip-addresses", same["id"].(int)); stored["dns_name"] != "host.example.com" {
+		t.Errorf("dns_name = %v", stored["dns_name"])
+	}
+	if entries := srv.Objects("/extras/journal-entries"); len(entries) != 1 {
+		t.Errorf("journal entries = %d, want 1", len(entries))
+	}
+}
+
+func TestFindIPExact(t *testing.T) {
+	srv, c := newClient(t)
+	srv.PageSize = 1
+	v6 := srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "2001:db8::1/64", "status": "active"})
+	srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.1/24", "status": "active"})
+	srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.1/32", "status": "reserved"})
+	srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.1.1/24", "status": "active"})
+
+	ip, err := c.FindIPExact(netbox.IPQuery{Addr: netip.MustParseAddr("2001:db8::1")})
+	if err != nil || ip.ID != v6["id"] {
+		t.Errorf("FindIPExact(v6) = %d, %v, want %v", ip.ID, err, v6["id"])
+	}
+	if _, err := c.FindIPExact(netbox.IPQuery{Addr: netip.MustParseAddr("10.0.0.1")}); !errors.Is(err, netbox.ErrMultipleResults) {
+		t.Errorf("FindIPExact() error = %v, want %v", err, netbox.ErrMultipleResults)
+	}
+	ip, err = c.FindIPExact(netbox.IPQuery{Prefix: netip.MustParsePrefix("10.0.0.1/32")})
+	if err != nil || ip.Address != "10.0.0.1/32" {
+		t.Errorf("FindIPExact(/32) = %s, %v", ip.Address, err)
+	}
+	if _, err := c.FindIPExact(netbox.IPQuery{Addr: netip.MustParseAddr("10.9.9.9")}); !errors.Is(err, netbox.ErrNotFound) {
+		t.Errorf("FindIPExact() error = %v, want %v", err, netbox.ErrNotFound)
+	}
+
+	ips, err := c.SearchIPs(netbox.IPQuery{Parent: netip.MustParsePrefix("10.0.0.0/16"), Status: "active"})
+	if err != nil {
+		t.Fatalf("SearchIPs() error = %v", err)
+	}
+	if len(ips) != 2 {
+		t.Errorf("SearchIPs() returned %d addresses, want 2", len(ips))
+	}
+	results, err := c.SearchIP("10.0.0.1/24")
+	if err != nil || results.Count != 1 {
+		t.Errorf("SearchIP() = %d results, %v, want 1", results.Count, err)
+	}
+}
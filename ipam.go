@@ -0,0 +1,480 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+)
+
+type Prefix struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Family       AddressFamily          `json:"family"`
+	Display      string                 `json:"display"`
+	Prefix       string                 `json:"prefix"`
+	Site         *DisplayIDName         `json:"site"`
+	Vrf          *NestedVRF             `json:"vrf"`
+	Tenant       *DisplayIDName         `json:"tenant"`
+	Vlan         interface{}            `json:"vlan"`
+	Status       LabelValue             `json:"status"`
+	Role         *DisplayIDName         `json:"role"`
+	IsPool       bool                   `json:"is_pool"`
+	MarkUtilized bool                   `json:"mark_utilized"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	Children     int                    `json:"children"`
+	Depth        int                    `json:"_depth"`
+	Created      string                 `json:"created"`
+	LastUpdated  string                 `json:"last_updated"`
+}
+
+// PrefixEdit is used to add/update a prefix
+type PrefixEdit struct {
+	Prefix       string                 `json:"prefix,omitempty"`
+	Site         *int                   `json:"site,omitempty"`
+	Vrf          *int                   `json:"vrf,omitempty"`
+	Tenant       *int                   `json:"tenant,omitempty"`
+	Vlan         *int                   `json:"vlan,omitempty"`
+	Status       *string                `json:"status,omitempty"`
+	Role         *int                   `json:"role,omitempty"`
+	IsPool       *bool                  `json:"is_pool,omitempty"`
+	MarkUtilized *bool                  `json:"mark_utilized,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type Aggregate struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Family       AddressFamily          `json:"family"`
+	Display      string                 `json:"display"`
+	Prefix       string                 `json:"prefix"`
+	Rir          DisplayIDName          `json:"rir"`
+	Tenant       *DisplayIDName         `json:"tenant"`
+	DateAdded    *string                `json:"date_added"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	Created      string                 `json:"created"`
+	LastUpdated  string                 `json:"last_updated"`
+}
+
+// AggregateEdit is used to add/update an aggregate
+type AggregateEdit struct {
+	Prefix       string                 `json:"prefix,omitempty"`
+	Rir          *int                   `json:"rir,omitempty"`
+	Tenant       *int                   `json:"tenant,omitempty"`
+	DateAdded    *string                `json:"date_added,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type IPRange struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Family       AddressFamily          `json:"family"`
+	Display      string                 `json:"display"`
+	StartAddress string                 `json:"start_address"`
+	EndAddress   string                 `json:"end_address"`
+	Size         int                    `json:"size"`
+	Vrf          *NestedVRF             `json:"vrf"`
+	Tenant       *DisplayIDName         `json:"tenant"`
+	Status       LabelValue             `json:"status"`
+	Role         *DisplayIDName         `json:"role"`
+	MarkUtilized bool                   `json:"mark_utilized"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	Created      string                 `json:"created"`
+	LastUpdated  string                 `json:"last_updated"`
+}
+
+// IPRangeEdit is used to add/update an IP range
+type IPRangeEdit struct {
+	StartAddress string                 `json:"start_address,omitempty"`
+	EndAddress   string                 `json:"end_address,omitempty"`
+	Vrf          *int                   `json:"vrf,omitempty"`
+	Tenant       *int                   `json:"tenant,omitempty"`
+	Status       *string                `json:"status,omitempty"`
+	Role         *int                   `json:"role,omitempty"`
+	MarkUtilized *bool                  `json:"mark_utilized,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// RIR is a regional internet registry that aggregates are assigned from
+type RIR struct {
+	ID          int    `json:"id"`
+	URL         string `json:"url"`
+	Display     string `json:"display"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	IsPrivate   bool   `json:"is_private"`
+	Description string `json:"description"`
+}
+
+// NextIPOptions sets the fields of an address allocated by AllocateNextIP
+type NextIPOptions struct {
+	Status       string                 `json:"status,omitempty"`
+	DNSName      string                 `json:"dns_name,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Tenant       *int                   `json:"tenant,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// Network parses the prefix
+func (p Prefix) Network() (netip.Prefix, error) {
+	return netip.ParsePrefix(p.Prefix)
+}
+
+// SetPrefix sets the prefix, with host bits cleared
+func (e *PrefixEdit) SetPrefix(prefix netip.Prefix) {
+	e.Prefix = prefix.Masked().String()
+}
+
+// Network parses the aggregate's prefix
+func (a Aggregate) Network() (netip.Prefix, error) {
+	return netip.ParsePrefix(a.Prefix)
+}
+
+// SetPrefix sets the prefix, with host bits cleared
+func (e *AggregateEdit) SetPrefix(prefix netip.Prefix) {
+	e.Prefix = prefix.Masked().String()
+}
+
+// Bounds parses the first and last address of the range
+func (r IPRange) Bounds() (start netip.Prefix, end netip.Prefix, err error) {
+	if start, err = netip.ParsePrefix(r.StartAddress); err != nil {
+		return start, end, err
+	}
+	end, err = netip.ParsePrefix(r.EndAddress)
+	return start, end, err
+}
+
+// SetBounds sets the first and last address of the range.  Both share
+// the mask of start.
+func (e *IPRangeEdit) SetBounds(start netip.Prefix, end netip.Addr) {
+	e.StartAddress = start.String()
+	e.EndAddress = netip.PrefixFrom(end, start.Bits()).String()
+}
+
+// ListPrefixes returns all prefixes that match the filter.  Filter
+// needs to be given as a valid api filter (eg. within=10.0.0.0/8)
+func (c *Client) ListPrefixes(filter *string) ([]Prefix, error) {
+	return c.ListPrefixesCtx(context.Background(), filter)
+}
+
+// ListPrefixesCtx is like ListPrefixes but uses ctx for the requests.
+func (c *Client) ListPrefixesCtx(ctx context.Context, filter *string) ([]Prefix, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	prefixes, err := listAll[Prefix](ctx, c, "prefix", args)
+	if err != nil {
+		c.log.Error("error finding prefixes", "filter", filter, "error", err)
+	}
+	return prefixes, err
+}
+
+// PrefixQuery selects prefixes.  Zero values are not used as filters.
+type PrefixQuery struct {
+	// Prefix matches the prefix exactly.
+	Prefix netip.Prefix
+	// Within matches prefixes inside, but not equal to, the prefix.
+	Within netip.Prefix
+	// Contains matches prefixes containing the address.
+	Contains netip.Addr
+	// VrfID limits the search to the given VRF.  nil matches every VRF
+	// and 0 matches prefixes in the global table only.
+	VrfID *int
+	// SiteID limits the search to prefixes of the site.
+	SiteID int
+	// TenantID limits the search to prefixes of the tenant.
+	TenantID int
+	// Status limits the search to prefixes with the status, eg. "active".
+	Status string
+}
+
+func (q PrefixQuery) args() []string {
+	var args []string
+	if q.Prefix.IsValid() {
+		args = append(args, "prefix="+url.QueryEscape(q.Prefix.Masked().String()))
+	}
+	if q.Within.IsValid() {
+		args = append(args, "within="+url.QueryEscape(q.Within.Masked().String()))
+	}
+	if q.Contains.IsValid() {
+		args = append(args, "contains="+url.QueryEscape(q.Contains.String()))
+	}
+	if q.VrfID != nil {
+		args = append(args, vrfArg(*q.VrfID))
+	}
+	if q.SiteID != 0 {
+		args = append(args, fmt.Sprintf("site_id=%d", q.SiteID))
+	}
+	if q.TenantID != 0 {
+		args = append(args, fmt.Sprintf("tenant_id=%d", q.TenantID))
+	}
+	if q.Status != "" {
+		args = append(args, "status="+url.QueryEscape(q.Status))
+	}
+	return args
+}
+
+// SearchPrefixes returns every prefix matching the query
+func (c *Client) SearchPrefixes(query PrefixQuery) ([]Prefix, error) {
+	return c.SearchPrefixesCtx(context.Background(), query)
+}
+
+// SearchPrefixesCtx is like SearchPrefixes but uses ctx for the requests.
+func (c *Client) SearchPrefixesCtx(ctx context.Context, query PrefixQuery) ([]Prefix, error) {
+	prefixes, err := listAll[Prefix](ctx, c, "prefix", query.args()...)
+	if err != nil {
+		c.log.Error("error finding prefixes", "query", query.args(), "error", err)
+	}
+	return prefixes, err
+}
+
+// GetPrefix retrieves the prefix with the given ID
+func (c *Client) GetPrefix(id int) (Prefix, error) {
+	return c.GetPrefixCtx(context.Background(), id)
+}
+
+// GetPrefixCtx is like GetPrefix but uses ctx for the request.
+func (c *Client) GetPrefixCtx(ctx context.Context, id int) (Prefix, error) {
+	return getObject[Prefix](ctx, c, "prefix", id)
+}
+
+// AddPrefix creates a new prefix
+func (c *Client) AddPrefix(prefix PrefixEdit) (Prefix, error) {
+	return c.AddPrefixCtx(context.Background(), prefix)
+}
+
+// AddPrefixCtx is like AddPrefix but uses ctx for the request.
+func (c *Client) AddPrefixCtx(ctx context.Context, prefix PrefixEdit) (Prefix, error) {
+	return createObject[Prefix](ctx, c, "prefix", prefix)
+}
+
+// UpdatePrefix modifies the given fields of the prefix
+func (c *Client) UpdatePrefix(id int, prefix PrefixEdit) (Prefix, error) {
+	return c.UpdatePrefixCtx(context.Background(), id, prefix)
+}
+
+// UpdatePrefixCtx is like UpdatePrefix but uses ctx for the request.
+func (c *Client) UpdatePrefixCtx(ctx context.Context, id int, prefix PrefixEdit) (Prefix, error) {
+	return updateObject[Prefix](ctx, c, "prefix", id, prefix)
+}
+
+// DeletePrefix removes the prefix from Netbox
+func (c *Client) DeletePrefix(id int) error {
+	return c.DeletePrefixCtx(context.Background(), id)
+}
+
+// DeletePrefixCtx is like DeletePrefix but uses ctx for the request.
+func (c *Client) DeletePrefixCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "prefix", id)
+}
+
+// ListAggregates returns all aggregates that match the filter.  Filter
+// needs to be given as a valid api filter (eg. rir_id=1)
+func (c *Client) ListAggregates(filter *string) ([]Aggregate, error) {
+	return c.ListAggregatesCtx(context.Background(), filter)
+}
+
+// ListAggregatesCtx is like ListAggregates but uses ctx for the requests.
+func (c *Client) ListAggregatesCtx(ctx context.Context, filter *string) ([]Aggregate, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	aggregates, err := listAll[Aggregate](ctx, c, "aggregate", args)
+	if err != nil {
+		c.log.Error("error finding aggregates", "filter", filter, "error", err)
+	}
+	return aggregates, err
+}
+
+// GetAggregate retrieves the aggregate with the given ID
+func (c *Client) GetAggregate(id int) (Aggregate, error) {
+	return c.GetAggregateCtx(context.Background(), id)
+}
+
+// GetAggregateCtx is like GetAggregate but uses ctx for the request.
+func (c *Client) GetAggregateCtx(ctx context.Context, id int) (Aggregate, error) {
+	return getObject[Aggregate](ctx, c, "aggregate", id)
+}
+
+// AddAggregate creates a new aggregate
+func (c *Client) AddAggregate(aggregate AggregateEdit) (Aggregate, error) {
+	return c.AddAggregateCtx(context.Background(), aggregate)
+}
+
+// AddAggregateCtx is like AddAggregate but uses ctx for the request.
+func (c *Client) AddAggregateCtx(ctx context.Context, aggregate AggregateEdit) (Aggregate, error) {
+	return createObject[Aggregate](ctx, c, "aggregate", aggregate)
+}
+
+// UpdateAggregate modifies the given fields of the aggregate
+func (c *Client) UpdateAggregate(id int, aggregate AggregateEdit) (Aggregate, error) {
+	return c.UpdateAggregateCtx(context.Background(), id, aggregate)
+}
+
+// UpdateAggregateCtx is like UpdateAggregate but uses ctx for the request.
+func (c *Client) UpdateAggregateCtx(ctx context.Context, id int, aggregate AggregateEdit) (Aggregate, error) {
+	return updateObject[Aggregate](ctx, c, "aggregate", id, aggregate)
+}
+
+// DeleteAggregate removes the aggregate from Netbox
+func (c *Client) DeleteAggregate(id int) error {
+	return c.DeleteAggregateCtx(context.Background(), id)
+}
+
+// DeleteAggregateCtx is like DeleteAggregate but uses ctx for the request.
+func (c *Client) DeleteAggregateCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "aggregate", id)
+}
+
+// GetOrAddRIR retrieves the RIR by name, creating it if it does not exist
+func (c *Client) GetOrAddRIR(name string) (RIR, error) {
+	return c.GetOrAddRIRCtx(context.Background(), name)
+}
+
+// GetOrAddRIRCtx is like GetOrAddRIR but uses ctx for the requests.
+func (c *Client) GetOrAddRIRCtx(ctx context.Context, name string) (RIR, error) {
+	rir, err := findOne[RIR](ctx, c, "rir", fmt.Sprintf("slug=%s", Slugify(name)))
+	if errors.Is(err, ErrNotFound) {
+		data := map[string]interface{}{"name": name, "slug": Slugify(name)}
+		return createObject[RIR](ctx, c, "rir", data)
+	}
+	return rir, err
+}
+
+// ListIPRanges returns all IP ranges that match the filter.  Filter
+// needs to be given as a valid api filter (eg. parent=10.0.0.0/8)
+func (c *Client) ListIPRanges(filter *string) ([]IPRange, error) {
+	return c.ListIPRangesCtx(context.Background(), filter)
+}
+
+// ListIPRangesCtx is like ListIPRanges but uses ctx for the requests.
+func (c *Client) ListIPRangesCtx(ctx context.Context, filter *string) ([]IPRange, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	ranges, err := listAll[IPRange](ctx, c, "ip-range", args)
+	if err != nil {
+		c.log.Error("error finding ip ranges", "filter", filter, "error", err)
+	}
+	return ranges, err
+}
+
+// GetIPRange retrieves the IP range with the given ID
+func (c *Client) GetIPRange(id int) (IPRange, error) {
+	return c.GetIPRangeCtx(context.Background(), id)
+}
+
+// GetIPRangeCtx is like GetIPRange but uses ctx for the request.
+func (c *Client) GetIPRangeCtx(ctx context.Context, id int) (IPRange, error) {
+	return getObject[IPRange](ctx, c, "ip-range", id)
+}
+
+// AddIPRange creates a new IP range
+func (c *Client) AddIPRange(ipRange IPRangeEdit) (IPRange, error) {
+	return c.AddIPRangeCtx(context.Background(), ipRange)
+}
+
+// AddIPRangeCtx is like AddIPRange but uses ctx for the request.
+func (c *Client) AddIPRangeCtx(ctx context.Context, ipRange IPRangeEdit) (IPRange, error) {
+	return createObject[IPRange](ctx, c, "ip-range", ipRange)
+}
+
+// UpdateIPRange modifies the given fields of the IP range
+func (c *Client) UpdateIPRange(id int, ipRange IPRangeEdit) (IPRange, error) {
+	return c.UpdateIPRangeCtx(context.Background(), id, ipRange)
+}
+
+// UpdateIPRangeCtx is like UpdateIPRange but uses ctx for the request.
+func (c *Client) UpdateIPRangeCtx(ctx context.Context, id int, ipRange IPRangeEdit) (IPRange, error) {
+	return updateObject[IPRange](ctx, c, "ip-range", id, ipRange)
+}
+
+// DeleteIPRange removes the IP range from Netbox
+func (c *Client) DeleteIPRange(id int) error {
+	return c.DeleteIPRangeCtx(context.Background(), id)
+}
+
+// DeleteIPRangeCtx is like DeleteIPRange but uses ctx for the request.
+func (c *Client) DeleteIPRangeCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "ip-range", id)
+}
+
+// AllocateNextIP creates the first free address in the prefix.  Netbox
+// serializes allocations, so concurrent callers never receive the same
+// address.  ErrConflict is returned when the prefix is full.
+func (c *Client) AllocateNextIP(prefixID int, opts NextIPOptions) (IP, error) {
+	return c.AllocateNextIPCtx(context.Background(), prefixID, opts)
+}
+
+// AllocateNextIPCtx is like AllocateNextIP but uses ctx for the request.
+func (c *Client) AllocateNextIPCtx(ctx context.Context, prefixID int, opts NextIPOptions) (IP, error) {
+	ip := IP{}
+	url, err := c.modelURL(OpCreate, "prefix", prefixID)
+	if err != nil {
+		return ip, err
+	}
+	r := c.buildRequest(ctx).SetResult(&ip).SetBody(opts)
+	resp, err := r.Post(url + "available-ips/")
+	if err != nil {
+		c.log.Error("error allocating address", "prefix", prefixID, "error", err)
+		return ip, err
+	}
+	if err = checkStatus(resp); err != nil {
+		c.log.Error("error allocating address", "prefix", prefixID, "status", resp.StatusCode(), "error", err)
+		return ip, err
+	}
+	c.log.Info("allocated address", "prefix", prefixID, "address", ip.Address)
+	return ip, nil
+}
+
+// AllocateNextPrefix creates the first free child prefix of the given
+// length within the parent prefix.  ErrConflict is returned when no
+// prefix of that length is available.
+func (c *Client) AllocateNextPrefix(parentID int, length int) (Prefix, error) {
+	return c.AllocateNextPrefixCtx(context.Background(), parentID, length)
+}
+
+// AllocateNextPrefixCtx is like AllocateNextPrefix but uses ctx for the request.
+func (c *Client) AllocateNextPrefixCtx(ctx context.Context, parentID int, length int) (Prefix, error) {
+	prefix := Prefix{}
+	url, err := c.modelURL(OpCreate, "prefix", parentID)
+	if err != nil {
+		return prefix, err
+	}
+	data := map[string]interface{}{"prefix_length": length}
+	r := c.buildRequest(ctx).SetResult(&prefix).SetBody(data)
+	resp, err := r.Post(url + "available-prefixes/")
+	if err != nil {
+		c.log.Error("error allocating prefix", "parent", parentID, "length", length, "error", err)
+		return prefix, err
+	}
+	if err = checkStatus(resp); err != nil {
+		c.log.Error("error allocating prefix", "parent", parentID, "length", length, "status", resp.StatusCode(), "error", err)
+		return prefix, err
+	}
+	c.log.Info("allocated prefix", "parent", parentID, "prefix", prefix.Prefix)
+	return prefix, nil
+}
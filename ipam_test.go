@@ -0,0 +1,61 @@
+package netbox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestIPAMAllocation(t *testing.T) {
+	srv, c := newClient(t)
+	parent, err := c.AddPrefix(netbox.PrefixEdit{Prefix: "10.1.0.0/16"})
+	if err != nil {
+		t.Fatalf("AddPrefix() error = %v", err)
+	}
+	if parent.Family.Value != 4 {
+		t.Errorf("AddPrefix() family = %+v, want 4", parent.Family)
+	}
+	srv.Add("/ipam/prefixes", netboxtest.Object{"prefix": "10.1.0.0/24"})
+
+	child, err := c.AllocateNextPrefix(parent.ID, 24)
+	if err != nil {
+		t.Fatalf("AllocateNextPrefix() error = %v", err)
+	}
+	if child.Prefix != "10.1.1.0/24" {
+		t.Errorf("AllocateNextPrefix() = %s, want 10.1.1.0/24", child.Prefix)
+	}
+
+	small, err := c.AddPrefix(netbox.PrefixEdit{Prefix: "192.168.0.0/30"})
+	if err != nil {
+		t.Fatalf("AddPrefix() error = %v", err)
+	}
+	for _, want := range []string{"192.168.0.1/30", "192.168.0.2/30"} {
+		ip, err := c.AllocateNextIP(small.ID, netbox.NextIPOptions{Status: "active", DNSName: "host.example.com"})
+		if err != nil {
+			t.Fatalf("AllocateNextIP() error = %v", err)
+		}
+		if ip.Address != want || ip.DNSName != "host.example.com" {
+			t.Errorf("AllocateNextIP() = %s %s, want %s", ip.Address, ip.DNSName, want)
+		}
+	}
+	if _, err := c.AllocateNextIP(small.ID, netbox.NextIPOptions{}); !errors.Is(err, netbox.ErrConflict) {
+		t.Errorf("AllocateNextIP() on a full prefix error = %v, want %v", err, netbox.ErrConflict)
+	}
+
+	filter := "within=10.1.0.0/16"
+	prefixes, err := c.ListPrefixes(&filter)
+	if err != nil {
+		t.Fatalf("ListPrefixes() error = %v", err)
+	}
+	if len(prefixes) == 0 {
+		t.Errorf("ListPrefixes() returned no prefixes")
+	}
+	if err := c.DeletePrefix(child.ID); err != nil {
+		t.Fatalf("DeletePrefix() error = %v", err)
+	}
+	if _, err := c.GetPrefix(child.ID); !errors.Is(err, netbox.ErrNotFound) {
+		t.Errorf("GetPrefix() after delete error = %v, want %v", err, netbox.ErrNotFound)
+	}
+}
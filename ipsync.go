@@ -0,0 +1,271 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+)
+
+// IPSyncAction is a change SyncIPs makes to an ipaddress.
+type IPSyncAction string
+
+// IPSyncActions
+const (
+	IPCreate     IPSyncAction = "create"
+	IPReassign   IPSyncAction = "reassign"
+	IPUpdateMask IPSyncAction = "update-mask"
+	// IPUpdateStatus restores the status of an address that was
+	// discovered again, eg. after an earlier sync deprecated it.
+	IPUpdateStatus IPSyncAction = "update-status"
+	IPDeprecate    IPSyncAction = "deprecate"
+	IPDelete       IPSyncAction = "delete"
+	// IPConflict marks a discovered address that Netbox holds more than
+	// once, so SyncIPs cannot tell which record to assign.  It is never
+	// applied and its Err is set.
+	IPConflict IPSyncAction = "conflict"
+)
+
+// StaleIPPolicy decides what SyncIPs does with addresses assigned to the
+// device or virtualmachine that were not discovered.
+type StaleIPPolicy int
+
+// StaleIPPolicies
+const (
+	KeepStaleIPs StaleIPPolicy = iota
+	DeprecateStaleIPs
+	DeleteStaleIPs
+)
+
+// IPSyncOptions controls SyncIPs.
+type IPSyncOptions struct {
+	// DryRun plans the changes without making them.
+	DryRun bool
+	// Stale is applied to addresses no longer discovered.
+	Stale StaleIPPolicy
+	// Status of discovered addresses.  Defaults to "active".
+	Status string
+	// VrfID limits the sync to addresses in the VRF and is set on created
+	// addresses.  nil uses every VRF and 0 the global table only.
+	VrfID *int
+}
+
+func (o IPSyncOptions) status() string {
+	if o.Status == "" {
+		return "active"
+	}
+	return o.Status
+}
+
+// IPChange is a change planned or made by SyncIPs.
+type IPChange struct {
+	Action IPSyncAction
+	// Address is the discovered address, or the stale address.
+	Address netip.Prefix
+	// Interface and InterfaceID name the interface the address is
+	// assigned to.
+	Interface   string
+	InterfaceID int
+	// IPID is the ipaddress changed.  It is 0 for planned creations.
+	IPID int
+	// Err is set if the change failed.
+	Err error
+}
+
+// SyncIPs reconciles the addresses assigned to the interfaces of a device
+// or virtualmachine with the discovered addresses, keyed by interface
+// name.  desired is the full state of the object: addresses assigned to
+// it but missing from desired are handled as set by opts.Stale.
+//
+// Missing addresses are created, or reassigned when Netbox already has
+// them on another interface or unassigned.  Discovered addresses are
+// given opts.Status, so an address deprecated by an earlier sync is
+// restored when it is discovered again.  An address Netbox has more
+// than once, eg. in several VRFs, is preferred with the same mask length;
+// if that is still ambiguous an IPConflict is recorded and the other
+// addresses are synced.  The changes are returned with any failures and
+// conflicts joined in the error.  With opts.DryRun nothing is changed.
+func (c *Client) SyncIPs(netboxType string, objectID int64, desired map[string][]netip.Prefix, opts IPSyncOptions) ([]IPChange, error) {
+	return c.SyncIPsCtx(context.Background(), netboxType, objectID, desired, opts)
+}
+
+// SyncIPsCtx is like SyncIPs but uses ctx for the requests.
+func (c *Client) SyncIPsCtx(ctx context.Context, netboxType string, objectID int64, desired map[string][]netip.Prefix, opts IPSyncOptions) ([]IPChange, error) {
+	changes, err := c.planIPSync(ctx, netboxType, objectID, desired, opts)
+	if err != nil {
+		return changes, err
+	}
+	var errs []error
+	for i := range changes {
+		change := &changes[i]
+		if change.Action != IPConflict && !opts.DryRun {
+			change.Err = c.applyIPChange(ctx, netboxType, change, opts)
+		}
+		if change.Err != nil {
+			c.log.Error("error syncing address", "action", change.Action, "address", change.Address, "error", change.Err)
+			errs = append(errs, fmt.Errorf("%s %s: %w", change.Action, change.Address, change.Err))
+		}
+	}
+	return changes, errors.Join(errs...)
+}
+
+func (c *Client) planIPSync(ctx context.Context, netboxType string, objectID int64, desired map[string][]netip.Prefix, opts IPSyncOptions) ([]IPChange, error) {
+	intfs, err := c.GetInterfacesForObjectCtx(ctx, netboxType, objectID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]Interface)
+	names := make(map[int]string)
+	for _, intf := range intfs {
+		byName[intf.Name] = intf
+		names[intf.ID] = intf.Name
+	}
+
+	idField := "device_id"
+	if netboxType == "virtualmachine" {
+		idField = "virtual_machine_id"
+	}
+	args := []string{fmt.Sprintf("%s=%d", idField, objectID)}
+	if opts.VrfID != nil {
+		args = append(args, vrfArg(*opts.VrfID))
+	}
+	assigned, err := listAll[IP](ctx, c, "ipaddress", args...)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[netip.Addr]IP)
+	for _, ip := range assigned {
+		if addr, err := ip.Addr(); err == nil {
+			current[addr] = ip
+		}
+	}
+
+	ifNames := make([]string, 0, len(desired))
+	for name := range desired {
+		ifNames = append(ifNames, name)
+	}
+	sort.Strings(ifNames)
+
+	var changes []IPChange
+	seen := make(map[netip.Addr]bool)
+	for _, name := range ifNames {
+		intf, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("interface %s: %w", name, ErrNotFound)
+		}
+		for _, prefix := range desired[name] {
+			addr := prefix.Addr()
+			seen[addr] = true
+			change := IPChange{Address: prefix, Interface: name, InterfaceID: intf.ID}
+			existing, ok := current[addr]
+			if !ok {
+				existing, err = c.findSyncIP(ctx, prefix, opts.VrfID)
+				switch {
+				case errors.Is(err, ErrNotFound):
+					change.Action = IPCreate
+					changes = append(changes, change)
+					continue
+				case errors.Is(err, ErrMultipleResults):
+					change.Action = IPConflict
+					change.Err = err
+					changes = append(changes, change)
+					continue
+				case err != nil:
+					return nil, fmt.Errorf("address %s: %w", prefix, err)
+				}
+			}
+			change.IPID = existing.ID
+			have, _ := existing.Prefix()
+			switch {
+			case !ok || existing.AssignedObjectID != intf.ID:
+				change.Action = IPReassign
+			case have.Bits() != prefix.Bits():
+				change.Action = IPUpdateMask
+			case existing.Status.Value != opts.status():
+				change.Action = IPUpdateStatus
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	if opts.Stale == KeepStaleIPs {
+		return changes, nil
+	}
+	for _, ip := range assigned {
+		prefix, err := ip.Prefix()
+		if err != nil || seen[prefix.Addr()] {
+			continue
+		}
+		change := IPChange{Address: prefix, Interface: names[ip.AssignedObjectID], InterfaceID: ip.AssignedObjectID, IPID: ip.ID}
+		switch {
+		case opts.Stale == DeleteStaleIPs:
+			change.Action = IPDelete
+		case ip.Status.Value != "deprecated":
+			change.Action = IPDeprecate
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// findSyncIP returns the ipaddress holding the address of prefix in the
+// VRF.  When there are several, the one with the mask length of prefix is
+// returned if it is the only one.
+func (c *Client) findSyncIP(ctx context.Context, prefix netip.Prefix, vrfID *int) (IP, error) {
+	results, err := c.SearchIPsCtx(ctx, IPQuery{Addr: prefix.Addr(), VrfID: vrfID})
+	if err != nil {
+		return IP{}, err
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+	var exact []IP
+	for _, ip := range results {
+		if have, err := ip.Prefix(); err == nil && have == prefix {
+			exact = append(exact, ip)
+		}
+	}
+	switch {
+	case len(results) == 0:
+		return IP{}, ErrNotFound
+	case len(exact) == 1:
+		return exact[0], nil
+	}
+	return IP{}, fmt.Errorf("%w: %d", ErrMultipleResults, len(results))
+}
+
+func (c *Client) applyIPChange(ctx context.Context, netboxType string, change *IPChange, opts IPSyncOptions) error {
+	edit := IPEdit{}
+	status := opts.status()
+	switch change.Action {
+	case IPCreate, IPReassign, IPUpdateMask:
+		edit.SetAddress(change.Address)
+		if err := edit.AssignInterface(netboxType, change.InterfaceID); err != nil {
+			return err
+		}
+		edit.Status = &status
+	case IPUpdateStatus:
+		edit.Status = &status
+	case IPDeprecate:
+		status := "deprecated"
+		edit.Status = &status
+	case IPDelete:
+		return c.deleteObject(ctx, "ipaddress", change.IPID)
+	}
+
+	if change.Action != IPCreate {
+		_, err := c.UpdateIPCtx(ctx, change.IPID, edit)
+		return err
+	}
+	if opts.VrfID != nil && *opts.VrfID != 0 {
+		edit.Vrf = opts.VrfID
+	}
+	ip, err := c.CreateIPCtx(ctx, edit)
+	change.IPID = ip.ID
+	return err
+}
@@ -0,0 +1,138 @@
+package netbox_test
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestSyncIPs(t *testing.T) {
+	srv, c := newClient(t)
+	vm := srv.Add("/virtualization/virtual-machines", netboxtest.Object{"name": "web01"})
+	vmID := vm["id"].(int)
+	eth0 := srv.Add("/virtualization/interfaces", netboxtest.Object{"virtual_machine": vmID, "name": "eth0"})
+	eth1 := srv.Add("/virtualization/interfaces", netboxtest.Object{"virtual_machine": vmID, "name": "eth1"})
+	assign := func(address string, intf netboxtest.Object) netboxtest.Object {
+		return srv.Add("/ipam/ip-addresses", netboxtest.Object{
+			"address":              address,
+			"status":               "active",
+			"assigned_object_type": "virtualization.vminterface",
+			"assigned_object_id":   intf["id"],
+		})
+	}
+	kept := assign("10.0.0.10/24", eth0)
+	moved := assign("10.0.1.10/24", eth0)
+	remask := assign("10.0.2.10/32", eth1)
+	stale := assign("10.0.3.10/24", eth1)
+	unassigned := srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.4.10/24"})
+
+	desired := map[string][]netip.Prefix{
+		"eth0": {netip.MustParsePrefix("10.0.0.10/24"), netip.MustParsePrefix("10.0.5.10/24")},
+		"eth1": {
+			netip.MustParsePrefix("10.0.1.10/24"),
+			netip.MustParsePrefix("10.0.2.10/24"),
+			netip.MustParsePrefix("10.0.4.10/24"),
+		},
+	}
+	want := map[netbox.IPSyncAction]int{
+		netbox.IPCreate:     1,
+		netbox.IPReassign:   2,
+		netbox.IPUpdateMask: 1,
+		netbox.IPDeprecate:  1,
+	}
+	opts := netbox.IPSyncOptions{DryRun: true, Stale: netbox.DeprecateStaleIPs}
+	plan, err := c.SyncIPs("virtualmachine", int64(vmID), desired, opts)
+	if err != nil {
+		t.Fatalf("SyncIPs(dry run) error = %v", err)
+	}
+	got := make(map[netbox.IPSyncAction]int)
+	for _, change := range plan {
+		got[change.Action]++
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("SyncIPs(dry run) planned %v, want %v", got, want)
+	}
+	if len(srv.Objects("/ipam/ip-addresses")) != 5 {
+		t.Fatalf("SyncIPs(dry run) changed addresses")
+	}
+
+	opts.DryRun = false
+	if _, err := c.SyncIPs("virtualmachine", int64(vmID), desired, opts); err != nil {
+		t.Fatalf("SyncIPs() error = %v", err)
+	}
+	check := func(obj netboxtest.Object, address string, intf netboxtest.Object, status string) {
+		t.Helper()
+		stored, _ := srv.Get("/ipam/ip-addresses", obj["id"].(int))
+		gotStatus := ""
+		if s, ok := stored["status"].(map[string]any); ok {
+			gotStatus = fmt.Sprint(s["value"])
+		}
+		if stored["address"] != address || fmt.Sprint(stored["assigned_object_id"]) != fmt.Sprint(intf["id"]) || gotStatus != status {
+			t.Errorf("address %v = %v on %v, %v", obj["id"], stored["address"], stored["assigned_object_id"], stored["status"])
+		}
+	}
+	check(kept, "10.0.0.10/24", eth0, "active")
+	check(moved, "10.0.1.10/24", eth1, "active")
+	check(remask, "10.0.2.10/24", eth1, "active")
+	check(stale, "10.0.3.10/24", eth1, "deprecated")
+	check(unassigned, "10.0.4.10/24", eth1, "active")
+
+	plan, err = c.SyncIPs("virtualmachine", int64(vmID), desired, netbox.IPSyncOptions{DryRun: true, Stale: netbox.DeleteStaleIPs})
+	if err != nil || len(plan) != 1 || plan[0].Action != netbox.IPDelete || plan[0].IPID != stale["id"] {
+		t.Errorf("SyncIPs() after sync = %+v, %v, want only the stale address deleted", plan, err)
+	}
+	if _, err := c.SyncIPs("virtualmachine", int64(vmID), map[string][]netip.Prefix{"eth9": nil}, opts); !errors.Is(err, netbox.ErrNotFound) {
+		t.Errorf("SyncIPs() with an unknown interface error = %v, want %v", err, netbox.ErrNotFound)
+	}
+
+	// the deprecated address is discovered again on the same interface
+	desired["eth1"] = append(desired["eth1"], netip.MustParsePrefix("10.0.3.10/24"))
+	plan, err = c.SyncIPs("virtualmachine", int64(vmID), desired, opts)
+	if err != nil || len(plan) != 1 || plan[0].Action != netbox.IPUpdateStatus || plan[0].IPID != stale["id"] {
+		t.Errorf("SyncIPs() of a rediscovered address = %+v, %v, want its status updated", plan, err)
+	}
+	check(stale, "10.0.3.10/24", eth1, "active")
+	if plan, err = c.SyncIPs("virtualmachine", int64(vmID), desired, opts); err != nil || len(plan) != 0 {
+		t.Errorf("SyncIPs() again = %+v, %v, want no changes", plan, err)
+	}
+}
+
+func TestSyncIPsConflict(t *testing.T) {
+	srv, c := newClient(t)
+	vm := srv.Add("/virtualization/virtual-machines", netboxtest.Object{"name": "web01"})
+	vmID := vm["id"].(int)
+	srv.Add("/virtualization/interfaces", netboxtest.Object{"virtual_machine": vmID, "name": "eth0"})
+	red := srv.Add("/ipam/vrfs", netboxtest.Object{"name": "red"})
+	blue := srv.Add("/ipam/vrfs", netboxtest.Object{"name": "blue"})
+	// the same mask in two VRFs is ambiguous, a different mask is not
+	srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.1/24", "vrf": red["id"]})
+	srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.1/24", "vrf": blue["id"]})
+	exact := srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.2/24", "vrf": red["id"]})
+	srv.Add("/ipam/ip-addresses", netboxtest.Object{"address": "10.0.0.2/32", "vrf": blue["id"]})
+
+	desired := map[string][]netip.Prefix{"eth0": {
+		netip.MustParsePrefix("10.0.0.1/24"),
+		netip.MustParsePrefix("10.0.0.2/24"),
+		netip.MustParsePrefix("10.0.0.3/24"),
+	}}
+	changes, err := c.SyncIPs("virtualmachine", int64(vmID), desired, netbox.IPSyncOptions{})
+	if !errors.Is(err, netbox.ErrMultipleResults) {
+		t.Errorf("SyncIPs() error = %v, want %v", err, netbox.ErrMultipleResults)
+	}
+	want := []netbox.IPSyncAction{netbox.IPConflict, netbox.IPReassign, netbox.IPCreate}
+	if len(changes) != len(want) {
+		t.Fatalf("SyncIPs() = %+v, want %v", changes, want)
+	}
+	for i, change := range changes {
+		if change.Action != want[i] {
+			t.Errorf("change %d = %s %s, want %s", i, change.Action, change.Address, want[i])
+		}
+	}
+	if changes[1].IPID != exact["id"] || changes[1].Err != nil || changes[2].Err != nil {
+		t.Errorf("SyncIPs() = %+v, want the exact match reassigned and the new address created", changes)
+	}
+}
@@ -0,0 +1,171 @@
+package netbox
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Operation is a set of API operations supported by a model.
+type Operation uint8
+
+const (
+	OpList Operation = 1 << iota
+	OpGet
+	OpCreate
+	OpUpdate
+	OpDelete
+
+	OpAll      = OpList | OpGet | OpCreate | OpUpdate | OpDelete
+	OpReadOnly = OpList | OpGet
+)
+
+// Model describes a Netbox object type known to the client.
+type Model struct {
+	// Name is the name used throughout this package, eg. "device".
+	Name string
+	// Aliases are alternate names that resolve to the model.
+	Aliases []string
+	// Path is the API path below /api, eg. "/dcim/devices".
+	Path string
+	// ContentType is the app_label.model name Netbox uses for generic
+	// relations such as journal entries, eg. "dcim.device".
+	ContentType string
+	// Type is the Go type results are decoded into, if the package has one.
+	Type reflect.Type
+	// Operations are the API operations the model supports.
+	Operations Operation
+}
+
+// Supports reports whether the model supports every operation in op.
+func (m Model) Supports(op Operation) bool {
+	return m.Operations&op == op
+}
+
+// ErrUnknownModel is matched by an *UnknownModelError.
+var ErrUnknownModel = errors.New("unknown netbox model")
+
+// UnknownModelError is returned when a model name is not registered.
+type UnknownModelError struct {
+	Name string
+}
+
+func (e *UnknownModelError) Error() string {
+	return fmt.Sprintf("could not determine the path for model %s", e.Name)
+}
+
+func (e *UnknownModelError) Is(target error) bool {
+	return target == ErrUnknownModel
+}
+
+var registry = struct {
+	sync.RWMutex
+	models map[string]Model
+}{models: make(map[string]Model)}
+
+func init() {
+	for _, m := range builtinModels {
+		if err := RegisterModel(m); err != nil {
+			panic(err)
+		}
+	}
+}
+
+var builtinModels = []Model{
+	{Name: "site", Path: "/dcim/sites", ContentType: "dcim.site", Operations: OpAll},
+	{Name: "site-group", Path: "/dcim/site-groups", ContentType: "dcim.sitegroup", Type: reflect.TypeOf(Group{}), Operations: OpAll},
+	{Name: "location", Path: "/dcim/locations", ContentType: "dcim.location", Operations: OpAll},
+	{Name: "device", Path: "/dcim/devices", ContentType: "dcim.device", Type: reflect.TypeOf(DeviceOrVM{}), Operations: OpAll},
+	{Name: "manufacturer", Path: "/dcim/manufacturers", ContentType: "dcim.manufacturer", Type: reflect.TypeOf(Manufacturer{}), Operations: OpAll},
+	{Name: "device-type", Path: "/dcim/device-types", ContentType: "dcim.devicetype", Type: reflect.TypeOf(DeviceType{}), Operations: OpAll},
+	{Name: "device-role", Path: "/dcim/device-roles", ContentType: "dcim.devicerole", Type: reflect.TypeOf(DeviceRole{}), Operations: OpAll},
+	{Name: "platform", Path: "/dcim/platforms", ContentType: "dcim.platform", Type: reflect.TypeOf(Platform{}), Operations: OpAll},
+	{Name: "interface-template", Path: "/dcim/interface-templates", ContentType: "dcim.interfacetemplate", Type: reflect.TypeOf(ComponentTemplate{}), Operations: OpAll},
+	{Name: "console-port-template", Path: "/dcim/console-port-templates", ContentType: "dcim.consoleporttemplate", Type: reflect.TypeOf(ComponentTemplate{}), Operations: OpAll},
+	{Name: "power-port-template", Path: "/dcim/power-port-templates", ContentType: "dcim.powerporttemplate", Type: reflect.TypeOf(ComponentTemplate{}), Operations: OpAll},
+	{Name: "module-bay-template", Path: "/dcim/module-bay-templates", ContentType: "dcim.modulebaytemplate", Type: reflect.TypeOf(ComponentTemplate{}), Operations: OpAll},
+	{Name: "rack", Path: "/dcim/racks", ContentType: "dcim.rack", Type: reflect.TypeOf(Rack{}), Operations: OpAll},
+	{Name: "rack-role", Path: "/dcim/rack-roles", ContentType: "dcim.rackrole", Type: reflect.TypeOf(RackRole{}), Operations: OpAll},
+	{Name: "rack-reservation", Path: "/dcim/rack-reservations", ContentType: "dcim.rackreservation", Type: reflect.TypeOf(RackReservation{}), Operations: OpAll},
+	{Name: "interface", Path: "/dcim/interfaces", ContentType: "dcim.interface", Type: reflect.TypeOf(Interface{}), Operations: OpAll},
+	{Name: "front-port", Path: "/dcim/front-ports", ContentType: "dcim.frontport", Operations: OpAll},
+	{Name: "rear-port", Path: "/dcim/rear-ports", ContentType: "dcim.rearport", Operations: OpAll},
+	{Name: "cable", Path: "/dcim/cables", ContentType: "dcim.cable", Type: reflect.TypeOf(Cable{}), Operations: OpAll},
+	{Name: "virtualmachine", Aliases: []string{"virtual-machine"}, Path: "/virtualization/virtual-machines", ContentType: "virtualization.virtualmachine", Type: reflect.TypeOf(DeviceOrVM{}), Operations: OpAll},
+	{Name: "vminterface", Path: "/virtualization/interfaces", ContentType: "virtualization.vminterface", Type: reflect.TypeOf(Interface{}), Operations: OpAll},
+	{Name: "cluster", Path: "/virtualization/clusters", ContentType: "virtualization.cluster", Type: reflect.TypeOf(Cluster{}), Operations: OpAll},
+	{Name: "cluster-group", Path: "/virtualization/cluster-groups", ContentType: "virtualization.clustergroup", Type: reflect.TypeOf(ClusterGroup{}), Operations: OpAll},
+	{Name: "cluster-type", Path: "/virtualization/cluster-types", ContentType: "virtualization.clustertype", Type: reflect.TypeOf(ClusterType{}), Operations: OpAll},
+	{Name: "ipaddress", Aliases: []string{"ip-address"}, Path: "/ipam/ip-addresses", ContentType: "ipam.ipaddress", Type: reflect.TypeOf(IP{}), Operations: OpAll},
+	{Name: "aggregate", Path: "/ipam/aggregates", ContentType: "ipam.aggregate", Type: reflect.TypeOf(Aggregate{}), Operations: OpAll},
+	{Name: "prefix", Path: "/ipam/prefixes", ContentType: "ipam.prefix", Type: reflect.TypeOf(Prefix{}), Operations: OpAll},
+	{Name: "ip-range", Path: "/ipam/ip-ranges", ContentType: "ipam.iprange", Type: reflect.TypeOf(IPRange{}), Operations: OpAll},
+	{Name: "rir", Path: "/ipam/rirs", ContentType: "ipam.rir", Type: reflect.TypeOf(RIR{}), Operations: OpAll},
+	{Name: "vrf", Path: "/ipam/vrfs", ContentType: "ipam.vrf", Type: reflect.TypeOf(VRF{}), Operations: OpAll},
+	{Name: "route-target", Path: "/ipam/route-targets", ContentType: "ipam.routetarget", Type: reflect.TypeOf(RouteTarget{}), Operations: OpAll},
+	{Name: "vlan", Path: "/ipam/vlans", ContentType: "ipam.vlan", Type: reflect.TypeOf(VLAN{}), Operations: OpAll},
+	{Name: "vlan-group", Path: "/ipam/vlan-groups", ContentType: "ipam.vlangroup", Type: reflect.TypeOf(VLANGroup{}), Operations: OpAll},
+	{Name: "customfield", Aliases: []string{"custom-field"}, Path: "/extras/custom-fields", ContentType: "extras.customfield", Operations: OpAll},
+	{Name: "tag", Path: "/extras/tags", ContentType: "extras.tag", Type: reflect.TypeOf(Tag{}), Operations: OpAll},
+	{Name: "journal-entry", Path: "/extras/journal-entries", ContentType: "extras.journalentry", Operations: OpAll},
+	{Name: "tenant", Path: "/tenancy/tenants", ContentType: "tenancy.tenant", Type: reflect.TypeOf(Tenant{}), Operations: OpAll},
+	{Name: "tenant-group", Path: "/tenancy/tenant-groups", ContentType: "tenancy.tenantgroup", Type: reflect.TypeOf(TenantGroup{}), Operations: OpAll},
+	{Name: "contact", Path: "/tenancy/contacts", ContentType: "tenancy.contact", Type: reflect.TypeOf(Contact{}), Operations: OpAll},
+	{Name: "contact-role", Path: "/tenancy/contact-roles", ContentType: "tenancy.contactrole", Type: reflect.TypeOf(ContactRole{}), Operations: OpAll},
+	{Name: "contact-assignment", Path: "/tenancy/contact-assignments", ContentType: "tenancy.contactassignment", Type: reflect.TypeOf(ContactAssignment{}), Operations: OpAll},
+}
+
+// RegisterModel adds a model to the registry, or replaces the model of the
+// same name.  Callers can use it to reach plugin models, eg.
+//
+//	netbox.RegisterModel(netbox.Model{
+//		Name:        "bgp-session",
+//		Path:        "/plugins/bgp/session",
+//		ContentType: "netbox_bgp.bgpsession",
+//		Operations:  netbox.OpAll,
+//	})
+func RegisterModel(m Model) error {
+	if m.Name == "" || m.Path == "" {
+		return errors.New("a model requires a name and a path")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if old, ok := registry.models[m.Name]; ok {
+		for _, alias := range old.Aliases {
+			delete(registry.models, alias)
+		}
+	}
+	registry.models[m.Name] = m
+	for _, alias := range m.Aliases {
+		registry.models[alias] = m
+	}
+	return nil
+}
+
+// LookupModel returns the registered model with the given name or alias.
+// An *UnknownModelError is returned if there is none.
+func LookupModel(name string) (Model, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	m, ok := registry.models[name]
+	if !ok {
+		return m, &UnknownModelError{Name: name}
+	}
+	return m, nil
+}
+
+// Models returns every registered model ordered by name.
+func Models() []Model {
+	registry.RLock()
+	defer registry.RUnlock()
+	var models []Model
+	for name, m := range registry.models {
+		if name == m.Name {
+			models = append(models, m)
+		}
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return models
+}
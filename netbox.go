@@ -1,9 +1,11 @@
 package netbox
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"regexp"
 	"strings"
@@ -15,13 +17,9 @@ import (
 	"github.com/rsapc/hookcmd/models"
 )
 
-var ErrNotFound = errors.New("the requested object was not found")
-var ErrNotImplemented = errors.New("not implemented")
-
 const (
 	updateSitePath = "/dcim/sites/{id}/"
 	addSitePath    = "/dcim/sites/"
-	tenantPath     = "/tenancy/tenants/"
 )
 
 var slugregex *regexp.Regexp
@@ -41,14 +39,6 @@ type Tag struct {
 	Slug string `json:"slug"`
 }
 
-type Tenant struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Slug        string `json:"slug"`
-	Description string `json:"description"`
-	Tags        []Tag  `json:"tags"`
-}
-
 type Group struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
@@ -151,6 +141,12 @@ type IP struct {
 			Name    string `json:"name"`
 			URL     string `json:"url"`
 		} `json:"device"`
+		VirtualMachine struct {
+			Display string `json:"display"`
+			ID      int    `json:"id"`
+			Name    string `json:"name"`
+			URL     string `json:"url"`
+		} `json:"virtual_machine"`
 		Display  string `json:"display"`
 		ID       int    `json:"id"`
 		Name     string `json:"name"`
@@ -163,13 +159,10 @@ type IP struct {
 	Created            string `json:"created"`
 	CustomFields       struct {
 	} `json:"custom_fields"`
-	DNSName     string `json:"dns_name"`
-	Description string `json:"description"`
-	Display     string `json:"display"`
-	Family      struct {
-		Label string `json:"label"`
-		Value int    `json:"value"`
-	} `json:"family"`
+	DNSName     string        `json:"dns_name"`
+	Description string        `json:"description"`
+	Display     string        `json:"display"`
+	Family      AddressFamily `json:"family"`
 	ID          int           `json:"id"`
 	LastUpdated string        `json:"last_updated"`
 	NatInside   interface{}   `json:"nat_inside"`
@@ -189,7 +182,7 @@ type IP struct {
 	} `json:"tags"`
 	Tenant interface{} `json:"tenant"`
 	URL    string      `json:"url"`
-	Vrf    interface{} `json:"vrf"`
+	Vrf    *NestedVRF  `json:"vrf"`
 }
 
 func init() {
@@ -208,11 +201,31 @@ type Client struct {
 	token   string
 	baseURL string
 	log     models.Logger
+	retry   RetryPolicy
+	// listOpts holds the default paging for list calls made without
+	// ListOptions
+	listOpts *ListOptions
 }
 
-func NewClient(baseURL string, token string, logger models.Logger) *Client {
+// NewClient returns a Client for the Netbox instance at baseURL that
+// authenticates with token.  Options are applied in order.
+func NewClient(baseURL string, token string, logger models.Logger, opts ...Option) *Client {
+	o := &clientOptions{retry: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(o)
+	}
 	c := &Client{}
-	c.client = resty.New()
+	if o.httpClient != nil {
+		// the options change the client and its transport, so they work
+		// on copies and hc can still be shared with other code
+		hc := *o.httpClient
+		if t, ok := hc.Transport.(*http.Transport); ok {
+			hc.Transport = t.Clone()
+		}
+		c.client = resty.NewWithClient(&hc)
+	} else {
+		c.client = resty.New()
+	}
 	c.client.SetRedirectPolicy(resty.FlexibleRedirectPolicy(5))
 	c.baseURL = baseURL
 	c.token = token
@@ -220,12 +233,16 @@ func NewClient(baseURL string, token string, logger models.Logger) *Client {
 	if log, ok := logger.(*slog.Logger); ok {
 		c.log = log.With("service", "netbox")
 	}
+	c.initRetries()
+	c.applyOptions(o)
 
 	return c
 }
 
-func (c *Client) buildRequest() *resty.Request {
-	return c.client.NewRequest().SetAuthScheme("Token").SetAuthToken(c.token)
+// buildRequest returns a new authenticated request bound to ctx so the
+// call is abandoned when ctx is cancelled or its deadline passes.
+func (c *Client) buildRequest(ctx context.Context) *resty.Request {
+	return c.client.NewRequest().SetContext(ctx).SetAuthScheme("Token").SetAuthToken(c.token)
 }
 
 func (c *Client) buildURL(path string, args ...any) string {
@@ -233,16 +250,14 @@ func (c *Client) buildURL(path string, args ...any) string {
 	return fmt.Sprintf("%s/api%s", c.baseURL, urlPath)
 }
 
-func checkStatus(resp *resty.Response) error {
-	if resp.IsError() {
-		return fmt.Errorf("invalid response to %s %s: [%d] %v %v", resp.Request.Method, resp.Request.URL, resp.StatusCode(), resp.Error(), string(resp.Body()))
-	}
-	return nil
+func (c *Client) GetSite(id int) (interface{}, error) {
+	return c.GetSiteCtx(context.Background(), id)
 }
 
-func (c *Client) GetSite(id int) (interface{}, error) {
+// GetSiteCtx is like GetSite but uses ctx for the request.
+func (c *Client) GetSiteCtx(ctx context.Context, id int) (interface{}, error) {
 	obj := make(map[string]interface{})
-	r := c.buildRequest().SetResult(obj)
+	r := c.buildRequest(ctx).SetResult(obj)
 	url := fmt.Sprintf("%s/%d", c.buildURL("/dcim/sites"), id)
 	resp, err := r.Get(url)
 	if err != nil {
@@ -251,9 +266,9 @@ func (c *Client) GetSite(id int) (interface{}, error) {
 	return resp.Result(), checkStatus(resp)
 }
 
-func (c *Client) checkSite(param string, name string) (found bool, id int) {
+func (c *Client) checkSite(ctx context.Context, param string, name string) (found bool, id int) {
 	obj := &SearchResults{}
-	r := c.buildRequest().SetResult(obj).SetQueryParam(param, name)
+	r := c.buildRequest(ctx).SetResult(obj).SetQueryParam(param, name)
 	resp, err := r.Get(c.buildURL("/dcim/sites/"))
 	if err != nil {
 		return false, 0
@@ -271,7 +286,12 @@ func (c *Client) checkSite(param string, name string) (found bool, id int) {
 
 // FindMonitoredObject searches for the device or VM that has the requested monitoring_id custom field.
 func (c *Client) FindMonitoredObject(monitoringID int) (objectType string, objectID int64, err error) {
-	obj, err := c.FindMonitoredDevice(monitoringID)
+	return c.FindMonitoredObjectCtx(context.Background(), monitoringID)
+}
+
+// FindMonitoredObjectCtx is like FindMonitoredObject but uses ctx for the requests.
+func (c *Client) FindMonitoredObjectCtx(ctx context.Context, monitoringID int) (objectType string, objectID int64, err error) {
+	obj, err := c.FindMonitoredDeviceCtx(ctx, monitoringID)
 	if err != nil {
 		if !errors.Is(err, ErrNotFound) {
 			return "device", -1, err
@@ -279,39 +299,51 @@ func (c *Client) FindMonitoredObject(monitoringID int) (objectType string, objec
 	} else {
 		return "device", obj.ID, nil
 	}
-	obj, err = c.FindMonitoredVM(monitoringID)
+	obj, err = c.FindMonitoredVMCtx(ctx, monitoringID)
 	return "virtualmachine", obj.ID, err
 }
 
 // FindMonitoredDevice searches devices for the given monitoring_id custom field
 func (c *Client) FindMonitoredDevice(monitoringID int) (object MonitoredObject, err error) {
-	return c.searchMonitoredID(monitoringID, "device")
+	return c.FindMonitoredDeviceCtx(context.Background(), monitoringID)
+}
+
+// FindMonitoredDeviceCtx is like FindMonitoredDevice but uses ctx for the request.
+func (c *Client) FindMonitoredDeviceCtx(ctx context.Context, monitoringID int) (object MonitoredObject, err error) {
+	return c.searchMonitoredID(ctx, monitoringID, "device")
 }
 
 // FindMonitoredVM searches virtual machines for the given monitoring_id custom field
 func (c *Client) FindMonitoredVM(monitoringID int) (object MonitoredObject, err error) {
-	return c.searchMonitoredID(monitoringID, "virtualmachine")
+	return c.FindMonitoredVMCtx(context.Background(), monitoringID)
 }
 
-func (c *Client) searchMonitoredID(monitoringID int, objectType string) (object MonitoredObject, err error) {
-	path := GetPathForModel(objectType)
+// FindMonitoredVMCtx is like FindMonitoredVM but uses ctx for the request.
+func (c *Client) FindMonitoredVMCtx(ctx context.Context, monitoringID int) (object MonitoredObject, err error) {
+	return c.searchMonitoredID(ctx, monitoringID, "virtualmachine")
+}
+
+func (c *Client) searchMonitoredID(ctx context.Context, monitoringID int, objectType string) (object MonitoredObject, err error) {
+	path, err := c.modelURL(OpList, objectType)
+	if err != nil {
+		return object, err
+	}
 	obj := &MonitoringSearchResults{}
-	r := c.buildRequest().SetResult(obj)
-	url := c.buildURL(fmt.Sprintf("%s/?cf_monitoring_id=%d", path, monitoringID))
-	resp, err := r.Get(url)
+	r := c.buildRequest(ctx).SetResult(obj)
+	resp, err := r.Get(fmt.Sprintf("%s?cf_monitoring_id=%d", path, monitoringID))
 	if err != nil {
 		c.log.Error(fmt.Sprintf("error searching %s", r.URL), "err", err)
 		return object, err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error(fmt.Sprintf("%d searching %s", resp.StatusCode(), r.URL), "err", err)
-		return object, errors.New(string(resp.Body()))
+		return object, err
 	}
 	if obj.Count == 0 {
 		return object, ErrNotFound
 	}
 	if obj.Count > 1 {
-		return object, fmt.Errorf("too many objects found: %d", obj.Count)
+		return object, fmt.Errorf("%w: %d", ErrMultipleResults, obj.Count)
 	}
 	object = obj.Results[0]
 	object.ObjectType = objectType
@@ -320,112 +352,153 @@ func (c *Client) searchMonitoredID(monitoringID int, objectType string) (object
 
 // GetDeviceOrVMbyType will return a map representing the object provided by objectType and objectID
 func (c *Client) GetDeviceOrVMbyType(objectType string, objectID int64) (obj DeviceOrVM, err error) {
-	path := GetPathForModel(objectType)
-	if path == "" {
-		c.log.Error("could not determine the path for model %s", objectType)
-		return obj, fmt.Errorf("could not determine the path for model %s", objectType)
+	return c.GetDeviceOrVMbyTypeCtx(context.Background(), objectType, objectID)
+}
+
+// GetDeviceOrVMbyTypeCtx is like GetDeviceOrVMbyType but uses ctx for the request.
+func (c *Client) GetDeviceOrVMbyTypeCtx(ctx context.Context, objectType string, objectID int64) (obj DeviceOrVM, err error) {
+	m, err := LookupModel(objectType)
+	if err != nil {
+		c.log.Error("unknown model", "model", objectType, "error", err)
+		return obj, err
 	}
-	url := c.buildURL(path+"/%d/", objectID)
-	return c.GetDeviceOrVM(url)
+	url := c.buildURL(m.Path+"/%d/", objectID)
+	return c.GetDeviceOrVMCtx(ctx, url)
 }
 
 // GetObject returns a map representing a Netbox Object, retrieved from
 // the given URL
 func (c *Client) GetDeviceOrVM(url string) (DeviceOrVM, error) {
+	return c.GetDeviceOrVMCtx(context.Background(), url)
+}
+
+// GetDeviceOrVMCtx is like GetDeviceOrVM but uses ctx for the request.
+func (c *Client) GetDeviceOrVMCtx(ctx context.Context, url string) (DeviceOrVM, error) {
 	obj := DeviceOrVM{}
-	r := c.buildRequest().SetResult(&obj)
+	r := c.buildRequest(ctx).SetResult(&obj)
 	resp, err := r.Get(url)
 	if err != nil {
 		c.log.Error(fmt.Sprintf("error searching %s", r.URL), "err", err)
 		return obj, err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error(fmt.Sprintf("%d searching %s", resp.StatusCode(), r.URL), "err", err)
-		return obj, fmt.Errorf("%s: %s", resp.Error(), resp.Body())
+		return obj, err
 	}
 	setDeviceCustomFields(&obj)
 	return obj, err
 }
 
 func (c *Client) AddSite(data map[string]interface{}) error {
+	return c.AddSiteCtx(context.Background(), data)
+}
+
+// AddSiteCtx is like AddSite but uses ctx for the request.
+func (c *Client) AddSiteCtx(ctx context.Context, data map[string]interface{}) error {
 	obj := make(map[string]interface{})
-	r := c.buildRequest().SetResult(&obj)
+	r := c.buildRequest(ctx).SetResult(&obj)
 	r.SetBody(data)
 	resp, err := r.Post(c.buildURL(addSitePath))
 	if err != nil {
+		c.log.Error("error adding site", "site", data["name"], "error", err)
 		return err
 	}
-	log.Printf("added site: %v %s", obj["id"], data["name"])
-	err = checkStatus(resp)
-
-	return err
+	if err = checkStatus(resp); err != nil {
+		c.log.Error("error adding site", "site", data["name"], "error", err)
+		return err
+	}
+	c.log.Info("added site", "id", obj["id"], "site", data["name"])
+	return nil
 }
 
 // SetMonitoringID sets the monitoring_id custom field on the given object/id
 func (c *Client) SetMonitoringID(model string, modelID int64, devid int) error {
-	err := c.UpdateCustomFieldOnModel(model, modelID, "monitoring_id", devid)
+	return c.SetMonitoringIDCtx(context.Background(), model, modelID, devid)
+}
+
+// SetMonitoringIDCtx is like SetMonitoringID but uses ctx for the requests.
+func (c *Client) SetMonitoringIDCtx(ctx context.Context, model string, modelID int64, devid int) error {
+	err := c.UpdateCustomFieldOnModelCtx(ctx, model, modelID, "monitoring_id", devid)
 	if err != nil {
 		c.log.Error(err.Error())
-		c.AddJournalEntry(model, modelID, WarningLevel, fmt.Sprintf("failed to add monitoring_id: %d", devid))
+		c.AddJournalEntryCtx(ctx, model, modelID, WarningLevel, fmt.Sprintf("failed to add monitoring_id: %d", devid))
 		return err
 	} else {
 		msg := fmt.Sprintf("added monitoring_id %d to %s %d", devid, model, modelID)
-		c.AddJournalEntry(model, modelID, SuccessLevel, msg)
+		c.AddJournalEntryCtx(ctx, model, modelID, SuccessLevel, msg)
 	}
 	return err
 }
 
 // UpdateObjectWithMap takes an object and updates it
 func (c *Client) UpdateObject(model string, modelID int64, payload any) error {
-	path := GetPathForModel(model)
-	if path == "" {
-		c.log.Error("could not determine the path for model %s", model)
-		return fmt.Errorf("could not determine the path for model %s", model)
+	return c.UpdateObjectCtx(context.Background(), model, modelID, payload)
+}
+
+// UpdateObjectCtx is like UpdateObject but uses ctx for the request.
+func (c *Client) UpdateObjectCtx(ctx context.Context, model string, modelID int64, payload any) error {
+	m, err := LookupModel(model)
+	if err != nil {
+		c.log.Error("unknown model", "model", model, "error", err)
+		return err
 	}
-	path = fmt.Sprintf("%s/%d/", path, modelID)
-	return c.UpdateObjectByURL(c.buildURL(path), payload)
+	return c.UpdateObjectByURLCtx(ctx, c.buildURL(m.Path+"/%d/", modelID), payload)
 }
 
 // UpdateObjectWithMap takes an object and updates it
 func (c *Client) UpdateObjectWithMap(model string, modelID int64, payload map[string]interface{}) error {
-	path := GetPathForModel(model)
-	if path == "" {
-		c.log.Error("could not determine the path for model %s", model)
-		return fmt.Errorf("could not determine the path for model %s", model)
+	return c.UpdateObjectWithMapCtx(context.Background(), model, modelID, payload)
+}
+
+// UpdateObjectWithMapCtx is like UpdateObjectWithMap but uses ctx for the request.
+func (c *Client) UpdateObjectWithMapCtx(ctx context.Context, model string, modelID int64, payload map[string]interface{}) error {
+	m, err := LookupModel(model)
+	if err != nil {
+		c.log.Error("unknown model", "model", model, "error", err)
+		return err
 	}
-	path = fmt.Sprintf("%s/%d/", path, modelID)
-	return c.UpdateObjectByURL(c.buildURL(path), payload)
+	return c.UpdateObjectByURLCtx(ctx, c.buildURL(m.Path+"/%d/", modelID), payload)
 }
 
 func (c *Client) UpdateObjectByURL(url string, payload any) error {
+	return c.UpdateObjectByURLCtx(context.Background(), url, payload)
+}
+
+// UpdateObjectByURLCtx is like UpdateObjectByURL but uses ctx for the request.
+func (c *Client) UpdateObjectByURLCtx(ctx context.Context, url string, payload any) error {
 	c.log.Debug(fmt.Sprintf("Updating %s", url))
 	obj := make(map[string]interface{})
-	r := c.buildRequest().SetResult(&obj)
+	r := c.buildRequest(ctx).SetResult(&obj)
 	r.SetBody(payload)
 	resp, err := r.Patch(url)
 	if err != nil {
 		c.log.Warn(err.Error())
 		return err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error(fmt.Sprintf("invalid response from server: %d: %v", resp.StatusCode(), resp.Error()), "url", r.URL, "body", resp.Body())
-		return fmt.Errorf("netbox returned %d: %s", resp.StatusCode(), resp.Body())
+		return err
 	}
 	return nil
 }
 
 // DeleteObjectByURL will send a DELETE command to the provided URL
 func (c *Client) DeleteObjectByURL(url string) error {
+	return c.DeleteObjectByURLCtx(context.Background(), url)
+}
+
+// DeleteObjectByURLCtx is like DeleteObjectByURL but uses ctx for the request.
+func (c *Client) DeleteObjectByURLCtx(ctx context.Context, url string) error {
 	c.log.Debug(fmt.Sprintf("Deleting %s", url))
-	r := c.buildRequest()
+	r := c.buildRequest(ctx)
 	resp, err := r.Delete(url)
 	if err != nil {
 		c.log.Warn(err.Error())
 		return err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error(fmt.Sprintf("invalid response from server: %d: %v", resp.StatusCode(), resp.Error()), "url", r.URL, "body", resp.Body())
-		return fmt.Errorf("netbox returned %d: %s", resp.StatusCode(), resp.Body())
+		return err
 	}
 	return nil
 }
@@ -463,78 +536,98 @@ func replaceComments(comments string, text string) string {
 	return string(data)
 }
 
-func (c *Client) checkGroup(group *Group) {
+// checkGroup looks up the site group by slug, setting its ID, and adds
+// it if it does not exist
+func (c *Client) checkGroup(ctx context.Context, group *Group) error {
 	obj := &SearchResults{}
-	r := c.buildRequest().SetResult(obj).SetQueryParam("slug", group.Slug)
+	r := c.buildRequest(ctx).SetResult(obj).SetQueryParam("slug", group.Slug)
 	resp, err := r.Get(c.buildURL("/dcim/site-groups/"))
 	if err != nil {
-		log.Printf("error searching site-groups: %v\n", err)
-		return
+		c.log.Error("error searching site-groups", "group", group.Slug, "error", err)
+		return err
 	}
 	if err = checkStatus(resp); err != nil {
-		log.Printf("error checking status: %v", err)
-		return
+		c.log.Error("error checking status", "status", resp.StatusCode(), "error", err)
+		return err
 	}
-	resp.Result()
 	if obj.Count == 0 {
-		c.addGroup(group)
-	} else {
-		group.ID = obj.Results[0].ID
+		return c.addGroup(ctx, group)
 	}
+	group.ID = obj.Results[0].ID
+	return nil
 }
 
-func (c *Client) addGroup(group *Group) {
-	r := c.buildRequest().SetResult(group).SetBody(group)
+func (c *Client) addGroup(ctx context.Context, group *Group) error {
+	r := c.buildRequest(ctx).SetResult(group).SetBody(group)
 	resp, err := r.Post(c.buildURL("/dcim/site-groups/"))
 	if err != nil {
-		log.Fatalf("error adding group %s: %v\n", group.Name, err)
+		c.log.Error("error adding group", "group", group.Name, "error", err)
+		return err
 	}
 	if err = checkStatus(resp); err != nil {
-		log.Fatalf("error checking status: %v", err)
+		c.log.Error("error checking status", "status", resp.StatusCode(), "error", err)
+		return err
 	}
+	return nil
 }
 
-func (c *Client) checkTag(tag Tag) {
+// checkTag adds the tag if no tag with its slug exists
+func (c *Client) checkTag(ctx context.Context, tag Tag) error {
 	obj := &SearchResults{}
-	r := c.buildRequest().SetResult(obj).SetQueryParam("slug", tag.Slug)
+	r := c.buildRequest(ctx).SetResult(obj).SetQueryParam("slug", tag.Slug)
 	resp, err := r.Get(c.buildURL("/extras/tags/"))
 	if err != nil {
-		log.Printf("error searching tags: %v\n", err)
-		return
+		c.log.Error("error searching tags", "tag", tag.Slug, "error", err)
+		return err
 	}
 	if err = checkStatus(resp); err != nil {
-		log.Printf("error checking status: %v", err)
-		return
+		c.log.Error("error checking status", "status", resp.StatusCode(), "error", err)
+		return err
 	}
-	resp.Result()
 	if obj.Count == 0 {
-		c.AddTag(tag.Name, tag.Slug)
+		return c.AddTagCtx(ctx, tag.Name, tag.Slug)
 	}
+	return nil
 }
 
 // AddTag creates a new tag in Netbox
-func (c *Client) AddTag(name string, slug string) {
+func (c *Client) AddTag(name string, slug string) error {
+	return c.AddTagCtx(context.Background(), name, slug)
+}
+
+// AddTagCtx is like AddTag but uses ctx for the request.
+func (c *Client) AddTagCtx(ctx context.Context, name string, slug string) error {
 	data := make(map[string]interface{})
 	data["name"] = name
 	data["slug"] = slug
-	r := c.buildRequest()
+	r := c.buildRequest(ctx)
 	r.SetBody(data)
 	resp, err := r.Post(c.buildURL("/extras/tags/"))
 	if err != nil {
-		log.Printf("error adding tag: %v\n", err)
-		return
+		c.log.Error("error adding tag", "tag", name, "error", err)
+		return err
 	}
 	if err = checkStatus(resp); err != nil {
-		log.Printf("error checking status: %v", err)
-		return
+		c.log.Error("error checking status", "status", resp.StatusCode(), "error", err)
+		return err
 	}
-	log.Printf("added tag: %s", name)
+	c.log.Info("added tag", "tag", name)
+	return nil
 }
 
 // AddJournalEntry adds a new journal entry to a location
 func (c *Client) AddJournalEntry(model string, modelID int64, level JournalLevel, comments string, args ...any) error {
+	return c.AddJournalEntryCtx(context.Background(), model, modelID, level, comments, args...)
+}
+
+// AddJournalEntryCtx is like AddJournalEntry but uses ctx for the request.
+func (c *Client) AddJournalEntryCtx(ctx context.Context, model string, modelID int64, level JournalLevel, comments string, args ...any) error {
+	objectType, err := getObjectType(model)
+	if err != nil {
+		return err
+	}
 	data := make(map[string]interface{})
-	data["assigned_object_type"] = getObjectType(model)
+	data["assigned_object_type"] = objectType
 	data["assigned_object_id"] = modelID
 	data["comments"] = fmt.Sprintf(comments, args...)
 	levelStr := getJournalLevel(level)
@@ -542,7 +635,7 @@ func (c *Client) AddJournalEntry(model string, modelID int64, level JournalLevel
 		data["kind"] = levelStr
 	}
 
-	r := c.buildRequest()
+	r := c.buildRequest(ctx)
 	r.SetBody(data)
 	resp, err := r.Post(c.buildURL("/extras/journal-entries/"))
 	if err != nil {
@@ -552,6 +645,11 @@ func (c *Client) AddJournalEntry(model string, modelID int64, level JournalLevel
 }
 
 func (c *Client) AddLocation(site float64, row map[string]string) error {
+	return c.AddLocationCtx(context.Background(), site, row)
+}
+
+// AddLocationCtx is like AddLocation but uses ctx for the requests.
+func (c *Client) AddLocationCtx(ctx context.Context, site float64, row map[string]string) error {
 	data := make(map[string]interface{})
 	data["name"] = row["Service Street 1"]
 	data["slug"] = Slugify(fmt.Sprint(row["Service Street 1"]))
@@ -560,7 +658,7 @@ func (c *Client) AddLocation(site float64, row map[string]string) error {
 	data["tags"] = []Tag{apcTag, jobberTag}
 
 	obj := make(map[string]interface{})
-	r := c.buildRequest().SetResult(&obj)
+	r := c.buildRequest(ctx).SetResult(&obj)
 	r.SetBody(data)
 	resp, err := r.Post(c.buildURL("/dcim/locations/"))
 	if err != nil {
@@ -569,68 +667,9 @@ func (c *Client) AddLocation(site float64, row map[string]string) error {
 	if err = checkStatus(resp); err != nil {
 		return err
 	}
-	log.Printf("added location %v %s\n", obj["id"], obj["name"])
-	return c.AddJournalEntry("location", obj["id"].(int64), InfoLevel, row["comments"])
-}
-
-// GetOrAddTenant retrieves the named tenant, or creates it if
-// it does not exist
-func (c *Client) GetOrAddTenant(name string) (*Tenant, error) {
-	obj := &SearchResults{}
-	tenant := &Tenant{}
-	r := c.buildRequest().SetResult(obj).SetQueryParam("name", name)
-	resp, err := r.Get(c.buildURL(tenantPath))
-	if err != nil {
-		log.Printf("error searching tenants: %v\n", err)
-		return nil, err
-	}
-	if err = checkStatus(resp); err != nil {
-		log.Printf("error checking status: %v", err)
-		return nil, err
-	}
-	resp.Result()
-	if obj.Count == 0 {
-		tenant.Name = name
-		tenant.Slug = Slugify(name)
-		tenant.Tags = []Tag{apcTag, customerTag, jobberTag}
-		return c.addTenant(tenant)
-	} else {
-		return c.GetTenant(obj.Results[0].ID)
-	}
-}
-
-func (c *Client) addTenant(tenant *Tenant) (*Tenant, error) {
-	type TenantReq struct {
-		Tenant
-		Group int `json:"group"`
-	}
-	req := &TenantReq{*tenant, 1}
-	r := c.buildRequest().SetResult(tenant).SetBody(req)
-	resp, err := r.Post(c.buildURL(tenantPath))
-	if err != nil {
-		log.Fatalf("error adding tenant %s: %v\n", tenant.Name, err)
-	}
-	if err = checkStatus(resp); err != nil {
-		log.Fatalf("error checking status: %v", err)
-	}
-	log.Printf("  added tenant %d %s", tenant.ID, tenant.Name)
-	return tenant, nil
-}
-
-func (c *Client) GetTenant(id int) (*Tenant, error) {
-	log.Printf("  getting tennant %d\n", id)
-	tenant := &Tenant{ID: id}
-	r := c.buildRequest().SetResult(tenant).SetBody(tenant)
-	r.SetPathParam("id", fmt.Sprint(id))
-	resp, err := r.Post(c.buildURL(tenantPath + "{id}"))
-	if err != nil {
-		log.Fatalf("error getting tenant %s: %v\n", tenant.Name, err)
-	}
-	if err = checkStatus(resp); err != nil {
-		log.Fatalf("error checking status: %v", err)
-	}
-	return tenant, nil
-
+	id, _ := obj["id"].(float64)
+	c.log.Info("added location", "id", id, "location", obj["name"])
+	return c.AddJournalEntryCtx(ctx, "location", int64(id), InfoLevel, row["comments"])
 }
 
 // SearchDeviceAndVM searches both the devices and virtualmachines
@@ -640,19 +679,21 @@ func (c *Client) GetTenant(id int) (*Tenant, error) {
 // Args should be specified as
 // key=value (eg. has_primary_ip=true)
 func (c *Client) SearchDeviceAndVM(args ...string) ([]DeviceOrVM, error) {
+	return c.SearchDeviceAndVMCtx(context.Background(), args...)
+}
+
+// SearchDeviceAndVMCtx is like SearchDeviceAndVM but uses ctx for the requests.
+func (c *Client) SearchDeviceAndVMCtx(ctx context.Context, args ...string) ([]DeviceOrVM, error) {
 	var devices []DeviceOrVM
-	devices, err := c.SearchDevices(args...)
+	devices, err := c.SearchDevicesCtx(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
-	vms, err := c.SearchVMs(args...)
+	vms, err := c.SearchVMsCtx(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
 	devices = append(devices, vms...)
-	for _, dev := range devices {
-		setDeviceCustomFields(&dev)
-	}
 	return devices, nil
 }
 
@@ -660,39 +701,42 @@ func (c *Client) SearchDeviceAndVM(args ...string) ([]DeviceOrVM, error) {
 // endpoint for the given args.  Args should be specified as
 // key=value (eg. has_primary_ip=true)
 func (c *Client) SearchDevices(args ...string) ([]DeviceOrVM, error) {
-	return c.performDevVMsearch("device", args...)
+	return c.SearchDevicesCtx(context.Background(), args...)
+}
+
+// SearchDevicesCtx is like SearchDevices but uses ctx for the requests.
+func (c *Client) SearchDevicesCtx(ctx context.Context, args ...string) ([]DeviceOrVM, error) {
+	return c.performDevVMsearch(ctx, "device", args...)
+}
+
+// WalkDevices calls fn for each device matching args, one page at a
+// time, until fn returns false.  Args are given as for SearchDevices.
+func (c *Client) WalkDevices(fn func(DeviceOrVM) bool, args ...string) error {
+	return c.WalkDevicesCtx(context.Background(), nil, fn, args...)
+}
+
+// WalkDevicesCtx is like WalkDevices but uses ctx for the requests and opts for paging.
+func (c *Client) WalkDevicesCtx(ctx context.Context, opts *ListOptions, fn func(DeviceOrVM) bool, args ...string) error {
+	return c.walkDevVMs(ctx, "device", opts, fn, args...)
 }
 
 // performDevVMsearch executes the search for devices or VMs
-func (c *Client) performDevVMsearch(objectType string, args ...string) ([]DeviceOrVM, error) {
+func (c *Client) performDevVMsearch(ctx context.Context, objectType string, args ...string) ([]DeviceOrVM, error) {
 	var devices []DeviceOrVM
-	obj := DeviceVMSearchResults{}
-	r := c.buildRequest().SetResult(&obj)
-	path := GetPathForModel(objectType)
-	if path == "" {
-		c.log.Error("could not determine the path for model %s", objectType)
-		return devices, fmt.Errorf("could not determine the path for model %s", objectType)
-	}
-	queryArgs := buildQueryPath(args...)
-	initalURL := c.buildURL(path+"/?%s", queryArgs)
-	url := &initalURL
-	for url != nil {
-		resp, err := r.Get(*url)
-		if err != nil {
-			c.log.Error(fmt.Sprintf("error searching %s", r.URL), "err", err)
-			return devices, err
-		}
-		if resp.IsError() {
-			c.log.Error(fmt.Sprintf("%d searching %s", resp.StatusCode(), r.URL), "err", err)
-			return devices, fmt.Errorf("%s: %s", resp.Error(), resp.Body())
-		}
-		devices = append(devices, obj.Results...)
-		url = obj.Next
-	}
-	for _, dev := range devices {
+	err := c.walkDevVMs(ctx, objectType, nil, func(dev DeviceOrVM) bool {
+		devices = append(devices, dev)
+		return true
+	}, args...)
+	return devices, err
+}
+
+// walkDevVMs streams the devices or VMs matching args to fn with their
+// custom fields populated
+func (c *Client) walkDevVMs(ctx context.Context, objectType string, opts *ListOptions, fn func(DeviceOrVM) bool, args ...string) error {
+	return List(ctx, c, objectType, opts, func(dev DeviceOrVM) bool {
 		setDeviceCustomFields(&dev)
-	}
-	return devices, nil
+		return fn(dev)
+	}, args...)
 }
 
 // buildQueryPath concatenates a strgin of arguments onto a queryPath separated by &
@@ -707,43 +751,61 @@ func buildQueryPath(args ...string) string {
 }
 
 func (c *Client) Search(objectType string, resultObj any, args ...string) error {
-	path := GetPathForModel(objectType)
+	return c.SearchCtx(context.Background(), objectType, resultObj, args...)
+}
+
+// SearchCtx is like Search but uses ctx for the request.
+func (c *Client) SearchCtx(ctx context.Context, objectType string, resultObj any, args ...string) error {
+	url, err := c.modelURL(OpList, objectType)
+	if err != nil {
+		return err
+	}
 	if len(args) > 0 {
-		path = path + "/?%s"
+		url = url + "?" + buildQueryPath(args...)
 	}
-	queryArgs := buildQueryPath(args...)
-	url := c.buildURL(path, queryArgs)
-	req := c.buildRequest().SetResult(resultObj)
+	req := c.buildRequest(ctx).SetResult(resultObj)
 	resp, err := req.Get(url)
 	if err != nil {
 		c.log.Error("error communicating with netbox", "method", "GET", "url", url, "error", err)
 		return err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error("netbox returned an error response", "method", "GET", "url", url, "status", resp.StatusCode())
-		return fmt.Errorf("response error: %v", resp.Error())
+		return err
 	}
 	return nil
 }
 
 func (c *Client) GetByID(objectType string, resultObj interface{}, id int) (interface{}, error) {
-	path := GetPathForModel(objectType)
-	url := fmt.Sprintf("%s/%d", c.buildURL(path), id)
-	return c.GetByURL(url, resultObj)
+	return c.GetByIDCtx(context.Background(), objectType, resultObj, id)
+}
+
+// GetByIDCtx is like GetByID but uses ctx for the request.
+func (c *Client) GetByIDCtx(ctx context.Context, objectType string, resultObj interface{}, id int) (interface{}, error) {
+	url, err := c.modelURL(OpGet, objectType, id)
+	if err != nil {
+		return resultObj, err
+	}
+	return c.GetByURLCtx(ctx, url, resultObj)
 }
 
 // GetByURL is useful for iterating through search results using the Next URL value.
 // The response object can be a pointer or object and will be returned.
 func (c *Client) GetByURL(url string, obj interface{}) (interface{}, error) {
-	r := c.buildRequest().SetResult(obj)
+	return c.GetByURLCtx(context.Background(), url, obj)
+}
+
+// GetByURLCtx is like GetByURL but uses ctx for the request.
+func (c *Client) GetByURLCtx(ctx context.Context, url string, obj interface{}) (interface{}, error) {
+	r := c.buildRequest(ctx).SetResult(obj)
 	resp, err := r.Get(url)
 	if err != nil {
 		c.log.Error(fmt.Sprintf("error calling %s", r.URL), "err", err)
 		return obj, err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error(fmt.Sprintf("%d calling %s", resp.StatusCode(), r.URL), "err", err)
-		return obj, fmt.Errorf("%s: %s", resp.Error(), resp.Body())
+		return obj, err
 	}
 	return obj, err
 }
@@ -0,0 +1,115 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+func newTestClient(t *testing.T, handler http.Handler, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, "test-token", slog.New(slog.NewTextHandler(io.Discard, nil)), opts...)
+}
+
+func TestSearchCtxCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results := &ClusterResponse{}
+	err := c.SearchCtx(ctx, "cluster", results, "name=test")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SearchCtx() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestServerErrorsReturned(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "checkGroup", call: func() error { return c.checkGroup(ctx, &Group{Name: "Customer", Slug: "customer"}) }},
+		{name: "addGroup", call: func() error { return c.addGroup(ctx, &Group{Name: "Customer", Slug: "customer"}) }},
+		{name: "checkTag", call: func() error { return c.checkTag(ctx, apcTag) }},
+		{name: "AddTag", call: func() error { return c.AddTag("APC", "apc") }},
+		{name: "AddSite", call: func() error { return c.AddSite(map[string]interface{}{"name": "site"}) }},
+		{name: "addTenant", call: func() error {
+			_, err := c.addTenant(ctx, TenantEdit{Name: "tenant", Slug: "tenant"})
+			return err
+		}},
+		{name: "GetTenant", call: func() error {
+			_, err := c.GetTenant(1)
+			return err
+		}},
+		{name: "GetOrAddTenant", call: func() error {
+			_, err := c.GetOrAddTenant("tenant")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrServer) {
+				t.Errorf("%s() error = %v, want %v", tt.name, err, ErrServer)
+			}
+		})
+	}
+}
+
+func TestUnknownModelNoRequest(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.Write([]byte(`{}`))
+	}))
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "Search", call: func() error { return c.Search("devcie", &map[string]any{}, "name=x") }},
+		{name: "GetByID", call: func() error {
+			_, err := c.GetByID("devcie", &map[string]any{}, 5)
+			return err
+		}},
+		{name: "searchMonitoredID", call: func() error {
+			_, err := c.searchMonitoredID(context.Background(), 1, "devcie")
+			return err
+		}},
+		{name: "AddJournalEntry", call: func() error { return c.AddJournalEntry("devcie", 1, InfoLevel, "note") }},
+		{name: "AddCustomField", call: func() error { return c.AddCustomField("circuit", "Circuit", false, "device", "devcie") }},
+		{name: "GetContactAssignments", call: func() error {
+			_, err := c.GetContactAssignments("devcie", 1)
+			return err
+		}},
+		{name: "AssignContact", call: func() error {
+			_, err := c.AssignContact("devcie", 1, 2, 3, "")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var unknown *UnknownModelError
+			if !errors.As(err, &unknown) || unknown.Name != "devcie" {
+				t.Errorf("error = %v, want an UnknownModelError for devcie", err)
+			}
+		})
+	}
+}
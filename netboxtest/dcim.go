@@ -0,0 +1,155 @@
+package netboxtest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// maxTraceHops bounds a trace so cabling loops do not stall a test.
+const maxTraceHops = 32
+
+// trace returns an Action implementing the trace route of an interface or
+// port of the given content type.  It follows cables, and from a front
+// port continues at its rear port and from a rear port at the front port
+// mapped to it.
+func trace(contentType string) Action {
+	return func(s *Server, w http.ResponseWriter, r *http.Request, obj Object) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, Object{"detail": fmt.Sprintf("Method \"%s\" not allowed.", r.Method)})
+			return
+		}
+		hops := []any{}
+		ctype, id := contentType, toInt(obj["id"])
+		for i := 0; i < maxTraceHops; i++ {
+			near := []any{s.brief(assignedPath(ctype), id, true)}
+			cable, far := s.cableAt(ctype, id)
+			if cable == nil {
+				hops = append(hops, []any{near, nil, []any{}})
+				break
+			}
+			ends := make([]any, 0, len(far))
+			for _, end := range far {
+				ends = append(ends, s.brief(assignedPath(end.contentType), end.id, true))
+			}
+			hops = append(hops, []any{near, s.brief("/dcim/cables", toInt(cable["id"]), true), ends})
+			if len(far) != 1 || !s.passThrough(&far[0]) {
+				break
+			}
+			ctype, id = far[0].contentType, far[0].id
+		}
+		writeJSON(w, http.StatusOK, hops)
+	}
+}
+
+type termination struct {
+	contentType string
+	id          int
+}
+
+func terminations(value any) []termination {
+	list, _ := value.([]any)
+	var ends []termination
+	for _, item := range list {
+		if end, ok := item.(map[string]any); ok {
+			ends = append(ends, termination{fmt.Sprint(end["object_type"]), toInt(end["object_id"])})
+		}
+	}
+	return ends
+}
+
+// cableAt returns the cable attached to the object and the terminations
+// at its other end.
+func (s *Server) cableAt(contentType string, id int) (Object, []termination) {
+	for _, cable := range s.Stored("/dcim/cables") {
+		a, b := terminations(cable["a_terminations"]), terminations(cable["b_terminations"])
+		for _, end := range a {
+			if end.contentType == contentType && end.id == id {
+				return cable, b
+			}
+		}
+		for _, end := range b {
+			if end.contentType == contentType && end.id == id {
+				return cable, a
+			}
+		}
+	}
+	return nil, nil
+}
+
+// passThrough moves end from a front port to its rear port or from a rear
+// port to its front port.  It returns false if the path ends at end.
+func (s *Server) passThrough(end *termination) bool {
+	switch end.contentType {
+	case "dcim.frontport":
+		front, ok := s.Lookup("/dcim/front-ports", end.id)
+		if !ok || front["rear_port"] == nil {
+			return false
+		}
+		*end = termination{"dcim.rearport", toInt(front["rear_port"])}
+		return true
+	case "dcim.rearport":
+		for _, front := range s.Stored("/dcim/front-ports") {
+			if toInt(front["rear_port"]) == end.id {
+				*end = termination{"dcim.frontport", toInt(front["id"])}
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// elevation implements the elevation route of a rack.  Units are listed
+// top down for the face given by the face parameter, and are occupied by
+// devices mounted on that face or by full depth devices on either face.
+func elevation(s *Server, w http.ResponseWriter, r *http.Request, rack Object) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, Object{"detail": fmt.Sprintf("Method \"%s\" not allowed.", r.Method)})
+		return
+	}
+	face := r.URL.Query().Get("face")
+	if face == "" {
+		face = "front"
+	}
+	if face != "front" && face != "rear" {
+		writeJSON(w, http.StatusBadRequest, Object{"face": []string{fmt.Sprintf("\"%s\" is not a valid choice.", face)}})
+		return
+	}
+	height, start := toInt(rack["u_height"]), toInt(rack["starting_unit"])
+	if height == 0 {
+		height = 42
+	}
+	if start == 0 {
+		start = 1
+	}
+
+	occupant := make(map[int]int)
+	for _, device := range s.Stored("/dcim/devices") {
+		if toInt(device["rack"]) != toInt(rack["id"]) || device["position"] == nil {
+			continue
+		}
+		size, fullDepth := 1, false
+		if dt, ok := s.Lookup("/dcim/device-types", toInt(device["device_type"])); ok {
+			if dt["u_height"] != nil {
+				size = toInt(dt["u_height"])
+			}
+			fullDepth, _ = dt["is_full_depth"].(bool)
+		}
+		if device["face"] != face && !fullDepth {
+			continue
+		}
+		for u := toInt(device["position"]); u < toInt(device["position"])+size; u++ {
+			occupant[u] = toInt(device["id"])
+		}
+	}
+
+	units := []any{}
+	for u := start + height - 1; u >= start; u-- {
+		unit := Object{"id": u, "name": fmt.Sprintf("U%d", u), "face": choice(face), "device": nil, "occupied": false}
+		if id, ok := occupant[u]; ok {
+			unit["device"] = s.brief("/dcim/devices", id, true)
+			unit["occupied"] = true
+		}
+		units = append(units, unit)
+	}
+	writeJSON(w, http.StatusOK, Object{"count": len(units), "next": nil, "previous": nil, "results": units})
+}
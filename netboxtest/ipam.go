@@ -0,0 +1,149 @@
+package netboxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strconv"
+)
+
+// maxPrefixCandidates bounds the search for a free child prefix so large
+// IPv6 parents do not stall a test.
+const maxPrefixCandidates = 1 << 16
+
+// availableIPs implements /ipam/prefixes/{id}/available-ips/.  GET lists
+// free addresses and POST creates the first one.
+func availableIPs(s *Server, w http.ResponseWriter, r *http.Request, obj Object) {
+	prefix, err := netip.ParsePrefix(fmt.Sprint(obj["prefix"]))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, Object{"detail": err.Error()})
+		return
+	}
+	used := make(map[netip.Addr]bool)
+	for _, ip := range s.Stored("/ipam/ip-addresses") {
+		if p, err := netip.ParsePrefix(fmt.Sprint(ip["address"])); err == nil && sameVRF(ip, obj) {
+			used[p.Addr()] = true
+		}
+	}
+	want := DefaultPageSize
+	if r.Method == http.MethodPost {
+		want = 1
+	} else if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		want = l
+	}
+
+	var free []netip.Addr
+	first := prefix.Masked().Addr()
+	hostsOnly := first.Is4() && prefix.Bits() < 31
+	if hostsOnly {
+		first = first.Next()
+	}
+	for a := first; a.IsValid() && prefix.Contains(a) && len(free) < want; a = a.Next() {
+		if hostsOnly && !prefix.Contains(a.Next()) {
+			break
+		}
+		if !used[a] {
+			free = append(free, a)
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		out := make([]any, 0, len(free))
+		for _, a := range free {
+			address := fmt.Sprintf("%s/%d", a, prefix.Bits())
+			out = append(out, Object{"family": family(address, true), "address": address, "vrf": obj["vrf"]})
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPost:
+		body := Object{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, Object{"detail": fmt.Sprintf("JSON parse error - %v", err)})
+			return
+		}
+		if len(free) == 0 {
+			writeJSON(w, http.StatusConflict, Object{"detail": "An insufficient number of IP addresses are available within prefix " + prefix.String()})
+			return
+		}
+		body["address"] = fmt.Sprintf("%s/%d", free[0], prefix.Bits())
+		body["vrf"] = obj["vrf"]
+		writeJSON(w, http.StatusCreated, s.Create("/ipam/ip-addresses", body))
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, Object{"detail": fmt.Sprintf("Method \"%s\" not allowed.", r.Method)})
+	}
+}
+
+// availablePrefixes implements POST /ipam/prefixes/{id}/available-prefixes/
+// by creating the first free child prefix of the requested length.
+func availablePrefixes(s *Server, w http.ResponseWriter, r *http.Request, obj Object) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, Object{"detail": fmt.Sprintf("Method \"%s\" not allowed.", r.Method)})
+		return
+	}
+	parent, err := netip.ParsePrefix(fmt.Sprint(obj["prefix"]))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, Object{"detail": err.Error()})
+		return
+	}
+	parent = parent.Masked()
+	body := Object{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, Object{"detail": fmt.Sprintf("JSON parse error - %v", err)})
+		return
+	}
+	length := toInt(body["prefix_length"])
+	if length <= parent.Bits() || length > parent.Addr().BitLen() {
+		writeJSON(w, http.StatusBadRequest, Object{"prefix_length": []string{"Invalid prefix length."}})
+		return
+	}
+
+	var children []netip.Prefix
+	for _, p := range s.Stored("/ipam/prefixes") {
+		child, err := netip.ParsePrefix(fmt.Sprint(p["prefix"]))
+		if err == nil && sameVRF(p, obj) && child.Bits() > parent.Bits() && parent.Overlaps(child) {
+			children = append(children, child)
+		}
+	}
+	shift := parent.Addr().BitLen() - length
+	for i, a := 0, parent.Addr(); i < maxPrefixCandidates && a.IsValid() && parent.Contains(a); i++ {
+		candidate := netip.PrefixFrom(a, length)
+		taken := false
+		for _, child := range children {
+			if child.Overlaps(candidate) {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			delete(body, "prefix_length")
+			body["prefix"] = candidate.String()
+			body["vrf"] = obj["vrf"]
+			writeJSON(w, http.StatusCreated, s.Create("/ipam/prefixes", body))
+			return
+		}
+		a = addPow2(a, shift)
+	}
+	writeJSON(w, http.StatusConflict, Object{"detail": "Insufficient space is available to accommodate the requested prefix size(s)"})
+}
+
+// addPow2 returns a + 2^shift, or the zero Addr on overflow.
+func addPow2(a netip.Addr, shift int) netip.Addr {
+	b := a.AsSlice()
+	i := len(b) - 1 - shift/8
+	carry := 1 << (shift % 8)
+	for ; i >= 0 && carry > 0; i-- {
+		sum := int(b[i]) + carry
+		b[i] = byte(sum)
+		carry = sum >> 8
+	}
+	if carry > 0 {
+		return netip.Addr{}
+	}
+	next, _ := netip.AddrFromSlice(b)
+	return next
+}
+
+func sameVRF(a, b Object) bool {
+	return fmt.Sprint(refID(a["vrf"])) == fmt.Sprint(refID(b["vrf"]))
+}
@@ -0,0 +1,240 @@
+package netboxtest
+
+// defaultResources returns the endpoints the fake serves out of the box.
+func defaultResources() map[string]Resource {
+	return map[string]Resource{
+		"/dcim/sites": {
+			Refs:     map[string]string{"group": "/dcim/site-groups", "tenant": "/tenancy/tenants"},
+			Choices:  []string{"status"},
+			Required: []string{"name", "slug"},
+		},
+		"/dcim/site-groups": {
+			Refs:     map[string]string{"parent": "/dcim/site-groups"},
+			Required: []string{"name", "slug"},
+		},
+		"/dcim/locations": {
+			Refs:     map[string]string{"site": "/dcim/sites", "parent": "/dcim/locations", "tenant": "/tenancy/tenants"},
+			Choices:  []string{"status"},
+			Required: []string{"name", "slug", "site"},
+		},
+		"/dcim/devices": {
+			Refs: map[string]string{
+				"site":        "/dcim/sites",
+				"location":    "/dcim/locations",
+				"rack":        "/dcim/racks",
+				"device_type": "/dcim/device-types",
+				"role":        "/dcim/device-roles",
+				"device_role": "/dcim/device-roles",
+				"platform":    "/dcim/platforms",
+				"tenant":      "/tenancy/tenants",
+				"cluster":     "/virtualization/clusters",
+				"primary_ip":  "/ipam/ip-addresses",
+				"primary_ip4": "/ipam/ip-addresses",
+				"primary_ip6": "/ipam/ip-addresses",
+			},
+			Choices:  []string{"status", "face"},
+			Required: []string{"name", "device_type", "site"},
+		},
+		"/dcim/manufacturers": {
+			Required: []string{"name", "slug"},
+		},
+		"/dcim/device-types": {
+			Refs:     map[string]string{"manufacturer": "/dcim/manufacturers"},
+			Choices:  []string{"airflow", "weight_unit"},
+			Required: []string{"manufacturer", "model", "slug"},
+		},
+		"/dcim/interface-templates": {
+			Refs:     map[string]string{"device_type": "/dcim/device-types"},
+			Choices:  []string{"type"},
+			Required: []string{"device_type", "name", "type"},
+		},
+		"/dcim/console-port-templates": {
+			Refs:     map[string]string{"device_type": "/dcim/device-types"},
+			Choices:  []string{"type"},
+			Required: []string{"device_type", "name"},
+		},
+		"/dcim/power-port-templates": {
+			Refs:     map[string]string{"device_type": "/dcim/device-types"},
+			Choices:  []string{"type"},
+			Required: []string{"device_type", "name"},
+		},
+		"/dcim/module-bay-templates": {
+			Refs:     map[string]string{"device_type": "/dcim/device-types"},
+			Required: []string{"device_type", "name"},
+		},
+		"/dcim/device-roles": {
+			Required: []string{"name", "slug"},
+		},
+		"/dcim/platforms": {
+			Refs:     map[string]string{"manufacturer": "/dcim/manufacturers"},
+			Required: []string{"name", "slug"},
+		},
+		"/dcim/racks": {
+			Refs:     map[string]string{"site": "/dcim/sites", "location": "/dcim/locations", "tenant": "/tenancy/tenants", "role": "/dcim/rack-roles"},
+			Choices:  []string{"status", "type", "outer_unit"},
+			Required: []string{"name", "site"},
+			Actions:  map[string]Action{"elevation": elevation},
+		},
+		"/dcim/rack-roles": {
+			Required: []string{"name", "slug"},
+		},
+		"/dcim/rack-reservations": {
+			Refs:     map[string]string{"rack": "/dcim/racks", "user": "/users/users", "tenant": "/tenancy/tenants"},
+			Required: []string{"rack", "units", "user", "description"},
+		},
+		"/dcim/interfaces": {
+			Refs: map[string]string{
+				"device":        "/dcim/devices",
+				"parent":        "/dcim/interfaces",
+				"lag":           "/dcim/interfaces",
+				"untagged_vlan": "/ipam/vlans",
+				"tagged_vlans":  "/ipam/vlans",
+				"vrf":           "/ipam/vrfs",
+			},
+			Choices:  []string{"type", "duplex", "mode"},
+			Required: []string{"device", "name", "type"},
+			Actions:  map[string]Action{"trace": trace("dcim.interface")},
+		},
+		"/dcim/front-ports": {
+			Refs:     map[string]string{"device": "/dcim/devices", "rear_port": "/dcim/rear-ports"},
+			Choices:  []string{"type"},
+			Required: []string{"device", "name", "type", "rear_port"},
+			Actions:  map[string]Action{"trace": trace("dcim.frontport")},
+		},
+		"/dcim/rear-ports": {
+			Refs:     map[string]string{"device": "/dcim/devices"},
+			Choices:  []string{"type"},
+			Required: []string{"device", "name", "type"},
+			Actions:  map[string]Action{"trace": trace("dcim.rearport")},
+		},
+		"/dcim/cables": {
+			Refs:     map[string]string{"tenant": "/tenancy/tenants"},
+			Choices:  []string{"status", "length_unit"},
+			Required: []string{"a_terminations", "b_terminations"},
+		},
+		"/virtualization/virtual-machines": {
+			Refs: map[string]string{
+				"site":        "/dcim/sites",
+				"cluster":     "/virtualization/clusters",
+				"tenant":      "/tenancy/tenants",
+				"role":        "/dcim/device-roles",
+				"platform":    "/dcim/platforms",
+				"primary_ip":  "/ipam/ip-addresses",
+				"primary_ip4": "/ipam/ip-addresses",
+				"primary_ip6": "/ipam/ip-addresses",
+			},
+			Choices:  []string{"status"},
+			Required: []string{"name"},
+		},
+		"/virtualization/interfaces": {
+			Refs: map[string]string{
+				"virtual_machine": "/virtualization/virtual-machines",
+				"parent":          "/virtualization/interfaces",
+				"untagged_vlan":   "/ipam/vlans",
+				"tagged_vlans":    "/ipam/vlans",
+				"vrf":             "/ipam/vrfs",
+			},
+			Choices:  []string{"mode"},
+			Required: []string{"virtual_machine", "name"},
+		},
+		"/virtualization/clusters": {
+			Refs: map[string]string{
+				"group":  "/virtualization/cluster-groups",
+				"type":   "/virtualization/cluster-types",
+				"site":   "/dcim/sites",
+				"tenant": "/tenancy/tenants",
+			},
+			Choices:  []string{"status"},
+			Required: []string{"name", "type"},
+		},
+		"/virtualization/cluster-groups": {
+			Required: []string{"name", "slug"},
+		},
+		"/virtualization/cluster-types": {
+			Required: []string{"name", "slug"},
+		},
+		"/ipam/ip-addresses": {
+			Refs:     map[string]string{"vrf": "/ipam/vrfs", "tenant": "/tenancy/tenants"},
+			Choices:  []string{"status", "role"},
+			Required: []string{"address"},
+		},
+		"/ipam/prefixes": {
+			Refs:     map[string]string{"site": "/dcim/sites", "vrf": "/ipam/vrfs", "tenant": "/tenancy/tenants"},
+			Choices:  []string{"status"},
+			Required: []string{"prefix"},
+			Actions: map[string]Action{
+				"available-ips":      availableIPs,
+				"available-prefixes": availablePrefixes,
+			},
+		},
+		"/ipam/aggregates": {
+			Refs:     map[string]string{"rir": "/ipam/rirs", "tenant": "/tenancy/tenants"},
+			Required: []string{"prefix", "rir"},
+		},
+		"/ipam/rirs": {
+			Required: []string{"name", "slug"},
+		},
+		"/ipam/ip-ranges": {
+			Refs:     map[string]string{"vrf": "/ipam/vrfs", "tenant": "/tenancy/tenants"},
+			Choices:  []string{"status"},
+			Required: []string{"start_address", "end_address"},
+		},
+		"/ipam/vrfs": {
+			Refs: map[string]string{
+				"tenant":         "/tenancy/tenants",
+				"import_targets": "/ipam/route-targets",
+				"export_targets": "/ipam/route-targets",
+			},
+			Required: []string{"name"},
+		},
+		"/ipam/route-targets": {
+			Refs:     map[string]string{"tenant": "/tenancy/tenants"},
+			Required: []string{"name"},
+		},
+		"/ipam/vlans": {
+			Refs: map[string]string{
+				"site":   "/dcim/sites",
+				"group":  "/ipam/vlan-groups",
+				"tenant": "/tenancy/tenants",
+			},
+			Choices:  []string{"status"},
+			Required: []string{"vid", "name"},
+		},
+		"/ipam/vlan-groups": {
+			Required: []string{"name", "slug"},
+		},
+		"/extras/custom-fields": {
+			Choices:  []string{"type"},
+			Required: []string{"name"},
+		},
+		"/extras/journal-entries": {
+			Choices:  []string{"kind"},
+			Required: []string{"assigned_object_type", "assigned_object_id", "comments"},
+		},
+		"/extras/tags": {
+			Required: []string{"name", "slug"},
+		},
+		"/tenancy/tenants": {
+			Refs:     map[string]string{"group": "/tenancy/tenant-groups"},
+			Required: []string{"name", "slug"},
+		},
+		"/tenancy/tenant-groups": {
+			Refs:     map[string]string{"parent": "/tenancy/tenant-groups"},
+			Required: []string{"name", "slug"},
+		},
+		"/tenancy/contacts": {
+			Required: []string{"name"},
+		},
+		"/tenancy/contact-roles": {
+			Required: []string{"name", "slug"},
+		},
+		"/tenancy/contact-assignments": {
+			Refs:     map[string]string{"contact": "/tenancy/contacts", "role": "/tenancy/contact-roles"},
+			Choices:  []string{"priority"},
+			Required: []string{"object_id", "contact"},
+		},
+		"/users/users": {
+			Required: []string{"username"},
+		},
+	}
+}
@@ -0,0 +1,677 @@
+// Package netboxtest provides an in-memory fake of the Netbox REST API for
+// testing code that uses netbox.Client without a live Netbox instance.
+//
+//	srv := netboxtest.NewServer("token")
+//	defer srv.Close()
+//	client := netbox.NewClient(srv.URL, "token", logger)
+//
+// The fake stores objects as decoded JSON, assigns IDs and URLs, renders
+// foreign keys and choice fields the way Netbox does, and supports
+// filtering, limit/offset pagination and token authentication.  It does
+// not validate fields beyond the required ones.
+package netboxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPageSize is the page size used when a list request has no limit.
+const DefaultPageSize = 50
+
+// Object is a Netbox object as stored by the fake server.
+type Object = map[string]any
+
+// Action handles a request to a detail route below an object, such as
+// /ipam/prefixes/{id}/available-ips/.  obj is the stored object and may be
+// modified while the server lock is held.
+type Action func(s *Server, w http.ResponseWriter, r *http.Request, obj Object)
+
+// Resource describes how the fake treats objects of one endpoint.
+type Resource struct {
+	// Refs maps fields holding foreign keys to the path of the endpoint
+	// they refer to, eg. "site": "/dcim/sites".
+	Refs map[string]string
+	// Choices lists fields rendered as {"value": ..., "label": ...}.
+	Choices []string
+	// Required lists fields that must be present when creating an object.
+	Required []string
+	// Actions are detail routes keyed by name.
+	Actions map[string]Action
+}
+
+// Server is an httptest.Server that implements the Netbox endpoints used
+// by the netbox package.
+type Server struct {
+	*httptest.Server
+	// Token is the API token clients must send.  An empty token accepts
+	// any request.
+	Token string
+	// PageSize is the number of results returned when a list request has
+	// no limit parameter.
+	PageSize int
+
+	mu        sync.Mutex
+	nextID    int
+	resources map[string]Resource
+	objects   map[string]map[int]Object
+}
+
+// NewServer starts a fake Netbox that requires the given token.
+func NewServer(token string) *Server {
+	s := &Server{
+		Token:     token,
+		PageSize:  DefaultPageSize,
+		resources: make(map[string]Resource),
+		objects:   make(map[string]map[int]Object),
+	}
+	for path, res := range defaultResources() {
+		s.Register(path, res)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Register adds or replaces the endpoint at path (eg. "/plugins/foo/bars"),
+// so tests can fake plugin models.
+func (s *Server) Register(path string, res Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path = strings.TrimSuffix(path, "/")
+	s.resources[path] = res
+	if s.objects[path] == nil {
+		s.objects[path] = make(map[int]Object)
+	}
+}
+
+// Add stores obj at the endpoint path as if it had been created through
+// the API and returns the rendered object.
+func (s *Server) Add(path string, obj Object) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.render(path, s.create(strings.TrimSuffix(path, "/"), obj))
+}
+
+// Get returns the rendered object at path with the given ID.
+func (s *Server) Get(path string, id int) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path = strings.TrimSuffix(path, "/")
+	obj, ok := s.objects[path][id]
+	if !ok {
+		return nil, false
+	}
+	return s.render(path, obj), true
+}
+
+// Objects returns every rendered object at path ordered by ID.
+func (s *Server) Objects(path string) []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path = strings.TrimSuffix(path, "/")
+	var objs []Object
+	for _, id := range s.ids(path) {
+		objs = append(objs, s.render(path, s.objects[path][id]))
+	}
+	return objs
+}
+
+// Lookup returns the stored object at path with the given ID for use
+// inside an Action.  The caller must hold the server lock, as Actions do.
+func (s *Server) Lookup(path string, id int) (Object, bool) {
+	obj, ok := s.objects[strings.TrimSuffix(path, "/")][id]
+	return obj, ok
+}
+
+// Stored returns the stored objects at path for use inside an Action.
+func (s *Server) Stored(path string) []Object {
+	path = strings.TrimSuffix(path, "/")
+	var objs []Object
+	for _, id := range s.ids(path) {
+		objs = append(objs, s.objects[path][id])
+	}
+	return objs
+}
+
+// Create stores obj at path for use inside an Action and returns it
+// rendered.
+func (s *Server) Create(path string, obj Object) Object {
+	path = strings.TrimSuffix(path, "/")
+	return s.render(path, s.create(path, obj))
+}
+
+// Render returns obj as the endpoint at path would, for use inside an
+// Action.
+func (s *Server) Render(path string, obj Object) Object {
+	return s.render(strings.TrimSuffix(path, "/"), obj)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Token != "" && r.Header.Get("Authorization") != "Token "+s.Token {
+		writeJSON(w, http.StatusForbidden, Object{"detail": "Invalid token"})
+		return
+	}
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.resources[path]; ok {
+		switch r.Method {
+		case http.MethodGet:
+			s.list(w, r, path)
+		case http.MethodPost:
+			s.post(w, r, path)
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, Object{"detail": fmt.Sprintf("Method \"%s\" not allowed.", r.Method)})
+		}
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	for i := len(parts) - 1; i > 0; i-- {
+		base := strings.Join(parts[:i], "/")
+		res, ok := s.resources[base]
+		if !ok {
+			continue
+		}
+		id, err := strconv.Atoi(parts[i])
+		obj, found := s.objects[base][id]
+		if err != nil || !found {
+			break
+		}
+		switch len(parts) - i {
+		case 1:
+			s.detail(w, r, base, obj)
+			return
+		case 2:
+			if action, ok := res.Actions[parts[i+1]]; ok {
+				action(s, w, r, obj)
+				return
+			}
+		}
+		break
+	}
+	writeJSON(w, http.StatusNotFound, Object{"detail": "Not found."})
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, path string) {
+	query := r.URL.Query()
+	limit := s.PageSize
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	var matches []Object
+	for _, id := range s.ids(path) {
+		obj := s.objects[path][id]
+		if s.matches(path, obj, query) {
+			matches = append(matches, obj)
+		}
+	}
+	body := Object{"count": len(matches), "next": nil, "previous": nil}
+	results := []Object{}
+	for i := offset; i < len(matches) && i < offset+limit; i++ {
+		results = append(results, s.render(path, matches[i]))
+	}
+	body["results"] = results
+	if offset+limit < len(matches) {
+		body["next"] = s.pageURL(r, limit, offset+limit)
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		body["previous"] = s.pageURL(r, limit, prev)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) pageURL(r *http.Request, limit int, offset int) string {
+	query := r.URL.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	return fmt.Sprintf("%s%s?%s", s.URL, r.URL.Path, query.Encode())
+}
+
+func (s *Server) post(w http.ResponseWriter, r *http.Request, path string) {
+	obj := Object{}
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		writeJSON(w, http.StatusBadRequest, Object{"detail": fmt.Sprintf("JSON parse error - %v", err)})
+		return
+	}
+	if errs := s.validate(path, obj); errs != nil {
+		writeJSON(w, http.StatusBadRequest, errs)
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.render(path, s.create(path, obj)))
+}
+
+func (s *Server) detail(w http.ResponseWriter, r *http.Request, path string, obj Object) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.render(path, obj))
+	case http.MethodPatch, http.MethodPut:
+		changes := Object{}
+		if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+			writeJSON(w, http.StatusBadRequest, Object{"detail": fmt.Sprintf("JSON parse error - %v", err)})
+			return
+		}
+		s.update(path, obj, changes)
+		writeJSON(w, http.StatusOK, s.render(path, obj))
+	case http.MethodDelete:
+		delete(s.objects[path], toInt(obj["id"]))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, Object{"detail": fmt.Sprintf("Method \"%s\" not allowed.", r.Method)})
+	}
+}
+
+func (s *Server) validate(path string, obj Object) Object {
+	var errs Object
+	for _, field := range s.resources[path].Required {
+		if v, ok := obj[field]; !ok || v == nil || v == "" {
+			if errs == nil {
+				errs = Object{}
+			}
+			errs[field] = []string{"This field is required."}
+		}
+	}
+	return errs
+}
+
+func (s *Server) create(path string, obj Object) Object {
+	s.nextID++
+	id := s.nextID
+	stored := Object{}
+	s.update(path, stored, obj)
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	stored["id"] = id
+	stored["url"] = fmt.Sprintf("%s/api%s/%d/", s.URL, path, id)
+	stored["created"] = now
+	if _, ok := stored["custom_fields"]; !ok {
+		stored["custom_fields"] = Object{}
+	}
+	if _, ok := stored["tags"]; !ok {
+		stored["tags"] = []any{}
+	}
+	s.objects[path][id] = stored
+	return stored
+}
+
+// update applies changes to obj.  Foreign keys given as nested objects are
+// stored as IDs and custom fields are merged as Netbox does.
+func (s *Server) update(path string, obj Object, changes Object) {
+	refs := s.resources[path].Refs
+	for field, value := range changes {
+		switch {
+		case field == "id" || field == "url":
+			continue
+		case field == "custom_fields":
+			cf, _ := obj[field].(Object)
+			if cf == nil {
+				cf = Object{}
+			}
+			if values, ok := value.(map[string]any); ok {
+				for k, v := range values {
+					cf[k] = v
+				}
+			}
+			obj[field] = cf
+		case refs[field] != "":
+			obj[field] = refID(value)
+		default:
+			obj[field] = value
+		}
+	}
+	obj["last_updated"] = time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// refID normalizes a foreign key given as an ID, a nested object or a
+// list of either.
+func refID(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		return toInt(v["id"])
+	case []any:
+		ids := make([]any, 0, len(v))
+		for _, item := range v {
+			ids = append(ids, refID(item))
+		}
+		return ids
+	}
+	return toInt(value)
+}
+
+func (s *Server) ids(path string) []int {
+	ids := make([]int, 0, len(s.objects[path]))
+	for id := range s.objects[path] {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// matches reports whether obj satisfies every filter in query.  Multiple
+// values for one filter match if any value matches.  Filters on fields the
+// object does not have match nothing, so a filter the fake does not know
+// fails a test rather than being silently ignored.
+func (s *Server) matches(path string, obj Object, query url.Values) bool {
+	refs := s.resources[path].Refs
+	for param, values := range query {
+		if param == "limit" || param == "offset" || param == "brief" || param == "ordering" {
+			continue
+		}
+		var match func(string) bool
+		switch {
+		case param == "id":
+			match = func(v string) bool { return strconv.Itoa(toInt(obj["id"])) == v }
+		case param == "q":
+			match = func(v string) bool {
+				return strings.Contains(strings.ToLower(fmt.Sprint(obj["name"], obj["address"])), strings.ToLower(v))
+			}
+		case param == "tag":
+			match = func(v string) bool { return hasTag(obj, v) }
+		case strings.HasPrefix(param, "cf_"):
+			match = func(v string) bool {
+				cf, _ := obj["custom_fields"].(Object)
+				return cf != nil && cf[strings.TrimPrefix(param, "cf_")] != nil && fmt.Sprint(cf[strings.TrimPrefix(param, "cf_")]) == v
+			}
+		case (param == "device_id" || param == "virtual_machine_id") && path == "/ipam/ip-addresses":
+			field := strings.TrimSuffix(param, "_id")
+			match = func(v string) bool { return s.assignedParentMatches(obj, field, v) }
+		case strings.HasSuffix(param, "_id") && refs[strings.TrimSuffix(param, "_id")] != "":
+			field := strings.TrimSuffix(param, "_id")
+			match = func(v string) bool { return refMatches(obj[field], v) }
+		case param == "address":
+			match = func(v string) bool { return addressMatches(obj["address"], v) }
+		case param == "parent" || param == "within" || param == "within_include":
+			include := param != "within"
+			match = func(v string) bool { return containedIn(obj, v, include) }
+		case param == "contains":
+			match = func(v string) bool { return contains(obj["prefix"], v) }
+		case refs[param] != "":
+			field := param
+			match = func(v string) bool { return s.refSlugMatches(refs[field], obj[field], v) }
+		default:
+			if _, ok := obj[param]; !ok {
+				return false
+			}
+			field := param
+			match = func(v string) bool { return valueMatches(obj[field], v) }
+		}
+		found := false
+		for _, v := range values {
+			if match(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(obj Object, slug string) bool {
+	tags, _ := obj["tags"].([]any)
+	for _, tag := range tags {
+		if t, ok := tag.(map[string]any); ok && t["slug"] == slug {
+			return true
+		}
+	}
+	return false
+}
+
+func refMatches(value any, id string) bool {
+	if id == "null" {
+		return value == nil
+	}
+	if ids, ok := value.([]any); ok {
+		for _, v := range ids {
+			if strconv.Itoa(toInt(v)) == id {
+				return true
+			}
+		}
+		return false
+	}
+	return value != nil && strconv.Itoa(toInt(value)) == id
+}
+
+// assignedParentMatches reports whether the interface obj is assigned to
+// belongs to the device or virtual machine with the given ID.
+func (s *Server) assignedParentMatches(obj Object, field string, id string) bool {
+	path := assignedPath(fmt.Sprint(obj["assigned_object_type"]))
+	intf, ok := s.objects[path][toInt(obj["assigned_object_id"])]
+	return ok && refMatches(intf[field], id)
+}
+
+// refSlugMatches handles filters such as site=<slug> that name a related
+// object by slug.
+func (s *Server) refSlugMatches(path string, value any, slug string) bool {
+	if value == nil {
+		return slug == "null"
+	}
+	target, ok := s.objects[path][toInt(value)]
+	return ok && (target["slug"] == slug || target["name"] == slug)
+}
+
+// addressMatches compares an address filter the way Netbox does: a bare
+// IP matches the host part of any prefix length.
+func addressMatches(value any, filter string) bool {
+	addr, ok := value.(string)
+	if !ok {
+		return false
+	}
+	if strings.Contains(filter, "/") {
+		want, err1 := netip.ParsePrefix(filter)
+		got, err2 := netip.ParsePrefix(addr)
+		return err1 == nil && err2 == nil && want == got
+	}
+	want, err := netip.ParseAddr(filter)
+	if err != nil {
+		return false
+	}
+	got, err := netip.ParsePrefix(addr)
+	return err == nil && got.Addr() == want
+}
+
+// containedIn reports whether the address or prefix of obj lies within
+// filter, a prefix.  include allows the prefix itself to match.
+func containedIn(obj Object, filter string, include bool) bool {
+	parent, err := netip.ParsePrefix(filter)
+	if err != nil {
+		return false
+	}
+	parent = parent.Masked()
+	value, ok := obj["address"].(string)
+	if !ok {
+		value, _ = obj["prefix"].(string)
+	}
+	p, err := netip.ParsePrefix(value)
+	if err != nil || !parent.Contains(p.Addr()) {
+		return false
+	}
+	if _, isAddress := obj["address"]; isAddress {
+		return true
+	}
+	return p.Bits() > parent.Bits() || (include && p.Bits() == parent.Bits())
+}
+
+// contains reports whether the prefix value contains the address or
+// prefix in filter.
+func contains(value any, filter string) bool {
+	p, err := netip.ParsePrefix(fmt.Sprint(value))
+	if err != nil {
+		return false
+	}
+	if want, err := netip.ParsePrefix(filter); err == nil {
+		return p.Bits() <= want.Bits() && p.Contains(want.Addr())
+	}
+	want, err := netip.ParseAddr(filter)
+	return err == nil && p.Contains(want)
+}
+
+func valueMatches(value any, filter string) bool {
+	switch v := value.(type) {
+	case nil:
+		return filter == "null"
+	case map[string]any:
+		return fmt.Sprint(v["value"]) == filter
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64) == filter
+	}
+	return fmt.Sprint(value) == filter
+}
+
+// render returns a copy of obj with foreign keys expanded into nested
+// objects and choice fields expanded into value/label pairs.
+func (s *Server) render(path string, obj Object) Object {
+	res := s.resources[path]
+	out := Object{}
+	for k, v := range obj {
+		out[k] = v
+	}
+	for field, target := range res.Refs {
+		value, ok := obj[field]
+		if !ok {
+			continue
+		}
+		if ids, isList := value.([]any); isList {
+			nested := make([]any, 0, len(ids))
+			for _, id := range ids {
+				nested = append(nested, s.brief(target, toInt(id), true))
+			}
+			out[field] = nested
+		} else if value != nil {
+			out[field] = s.brief(target, toInt(value), true)
+		}
+	}
+	for _, field := range res.Choices {
+		if v, ok := obj[field]; ok && v != nil {
+			out[field] = choice(v)
+		}
+	}
+	if addr, ok := obj["address"].(string); ok {
+		out["family"] = family(addr, false)
+	} else if prefix, ok := obj["prefix"].(string); ok {
+		out["family"] = family(prefix, false)
+	}
+	if assigned, ok := obj["assigned_object_type"].(string); ok && obj["assigned_object_id"] != nil {
+		if target := assignedPath(assigned); target != "" {
+			out["assigned_object"] = s.brief(target, toInt(obj["assigned_object_id"]), true)
+		}
+	}
+	out["display"] = display(obj)
+	return out
+}
+
+// brief renders the nested representation of a related object.
+func (s *Server) brief(path string, id int, nested bool) any {
+	obj, ok := s.objects[path][id]
+	if !ok {
+		return Object{"id": id}
+	}
+	out := Object{"id": id, "url": obj["url"], "display": display(obj)}
+	for _, field := range []string{"name", "slug", "address", "vid", "rd", "prefix", "model", "cable", "username"} {
+		if v, ok := obj[field]; ok {
+			out[field] = v
+		}
+	}
+	if addr, ok := obj["address"].(string); ok {
+		out["family"] = family(addr, true)
+	}
+	if nested {
+		for _, field := range []string{"device", "virtual_machine", "manufacturer"} {
+			if target := s.resources[path].Refs[field]; target != "" && obj[field] != nil {
+				out[field] = s.brief(target, toInt(obj[field]), false)
+			}
+		}
+	}
+	return out
+}
+
+func display(obj Object) string {
+	for _, field := range []string{"display", "name", "address", "prefix", "model", "username"} {
+		if v, ok := obj[field].(string); ok && v != "" {
+			return v
+		}
+	}
+	return fmt.Sprint(obj["id"])
+}
+
+func choice(value any) any {
+	if m, ok := value.(map[string]any); ok {
+		return m
+	}
+	v := fmt.Sprint(value)
+	label := strings.ReplaceAll(v, "-", " ")
+	if label != "" {
+		label = strings.ToUpper(label[:1]) + label[1:]
+	}
+	return Object{"value": value, "label": label}
+}
+
+// family returns the address family of a prefix, as a number in nested
+// objects or as a value/label pair otherwise.
+func family(address string, nested bool) any {
+	value := 4
+	if p, err := netip.ParsePrefix(address); err == nil && p.Addr().Is6() {
+		value = 6
+	}
+	if nested {
+		return value
+	}
+	return Object{"value": value, "label": fmt.Sprintf("IPv%d", value)}
+}
+
+func assignedPath(contentType string) string {
+	switch contentType {
+	case "dcim.interface":
+		return "/dcim/interfaces"
+	case "virtualization.vminterface":
+		return "/virtualization/interfaces"
+	case "dcim.frontport":
+		return "/dcim/front-ports"
+	case "dcim.rearport":
+		return "/dcim/rear-ports"
+	}
+	return ""
+}
+
+func toInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,140 @@
+package netboxtest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func newClient(t *testing.T) (*netboxtest.Server, *netbox.Client) {
+	t.Helper()
+	srv := netboxtest.NewServer("secret")
+	t.Cleanup(srv.Close)
+	return srv, netbox.NewClient(srv.URL, "secret", slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestToken(t *testing.T) {
+	srv, _ := newClient(t)
+	c := netbox.NewClient(srv.URL, "wrong", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if _, err := c.SearchDevices(); !errors.Is(err, netbox.ErrForbidden) {
+		t.Errorf("SearchDevices() with bad token error = %v, want %v", err, netbox.ErrForbidden)
+	}
+}
+
+func TestVirtualization(t *testing.T) {
+	_, c := newClient(t)
+	if _, err := c.AddClusterType("VMware"); err != nil {
+		t.Fatalf("AddClusterType() error = %v", err)
+	}
+	if _, err := c.GetOrAddClusterGroup("Lab"); err != nil {
+		t.Fatalf("GetOrAddClusterGroup() error = %v", err)
+	}
+	cluster, err := c.GetOrAddCluster("Lab", "esx01", "VMware")
+	if err != nil {
+		t.Fatalf("GetOrAddCluster() error = %v", err)
+	}
+	if cluster.Group.Name != "Lab" || cluster.Type.Name != "VMware" {
+		t.Errorf("GetOrAddCluster() = %+v, want group Lab and type VMware", cluster)
+	}
+	again, err := c.GetCluster("Lab", "esx01")
+	if err != nil || again.ID != cluster.ID {
+		t.Errorf("GetCluster() = %d, %v, want %d", again.ID, err, cluster.ID)
+	}
+
+	vm, err := c.AddVM(netbox.NewVM{ClusterID: cluster.ID, Name: "web01", Status: "active", VCPUs: 2})
+	if err != nil {
+		t.Fatalf("AddVM() error = %v", err)
+	}
+	if vm.Status.Value != "active" || vm.Status.Label != "Active" {
+		t.Errorf("AddVM() status = %+v", vm.Status)
+	}
+	name := "eth0"
+	if _, err := c.AddInterface("virtualmachine", int64(vm.ID), netbox.InterfaceEdit{Name: &name}); err != nil {
+		t.Fatalf("AddInterface() error = %v", err)
+	}
+	intf, err := c.FindInterfaceByName("virtualmachine", int64(vm.ID), "eth0")
+	if err != nil || intf.Name != "eth0" {
+		t.Errorf("FindInterfaceByName() = %+v, %v", intf, err)
+	}
+}
+
+func TestPaginationAndCustomFields(t *testing.T) {
+	srv, c := newClient(t)
+	srv.PageSize = 2
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	for i := 0; i < 5; i++ {
+		srv.Add("/dcim/devices", netboxtest.Object{"name": fmt.Sprintf("sw%d", i), "site": site["id"], "status": "active"})
+	}
+	devices, err := c.SearchDevices("site_id=" + fmt.Sprint(site["id"]))
+	if err != nil {
+		t.Fatalf("SearchDevices() error = %v", err)
+	}
+	if len(devices) != 5 {
+		t.Fatalf("SearchDevices() returned %d devices, want 5", len(devices))
+	}
+	if devices[0].Site.Name != "HQ" {
+		t.Errorf("device site = %+v, want HQ", devices[0].Site)
+	}
+
+	if err := c.SetMonitoringID("device", int64(devices[3].ID), 42); err != nil {
+		t.Fatalf("SetMonitoringID() error = %v", err)
+	}
+	obj, err := c.FindMonitoredDevice(42)
+	if err != nil || obj.ID != int64(devices[3].ID) {
+		t.Errorf("FindMonitoredDevice() = %+v, %v, want ID %d", obj, err, devices[3].ID)
+	}
+	if entries := srv.Objects("/extras/journal-entries"); len(entries) != 1 {
+		t.Errorf("journal entries = %d, want 1", len(entries))
+	}
+	if _, err := c.FindMonitoredVM(42); !errors.Is(err, netbox.ErrNotFound) {
+		t.Errorf("FindMonitoredVM() error = %v, want %v", err, netbox.ErrNotFound)
+	}
+}
+
+func TestUnknownFilterMatchesNothing(t *testing.T) {
+	srv, c := newClient(t)
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	srv.Add("/dcim/devices", netboxtest.Object{"name": "sw1", "site": site["id"], "status": "active"})
+	if devices, err := c.SearchDevices("status=active"); err != nil || len(devices) != 1 {
+		t.Errorf("SearchDevices(status=active) = %d, %v, want 1", len(devices), err)
+	}
+	if devices, err := c.SearchDevices("status=active", "serial_number=ABC"); err != nil || len(devices) != 0 {
+		t.Errorf("SearchDevices() with an unknown filter = %d, %v, want 0", len(devices), err)
+	}
+}
+
+func TestTenancyAndIPs(t *testing.T) {
+	srv, c := newClient(t)
+	tenant, err := c.GetOrAddTenant("Acme Corp")
+	if err != nil {
+		t.Fatalf("GetOrAddTenant() error = %v", err)
+	}
+	if tenant.Slug != "acme-corp" || tenant.Group == nil || tenant.Group.Name != "Customer" {
+		t.Errorf("GetOrAddTenant() = %+v", tenant)
+	}
+	again, err := c.GetOrAddTenant("Acme Corp")
+	if err != nil || again.ID != tenant.ID {
+		t.Errorf("GetOrAddTenant() second call = %+v, %v", again, err)
+	}
+
+	ip, err := c.AddIP("10.0.0.1/24")
+	if err != nil {
+		t.Fatalf("AddIP() error = %v", err)
+	}
+	if ip.Family.Value != 4 {
+		t.Errorf("AddIP() family = %+v, want 4", ip.Family)
+	}
+	if err := c.SetIPDNS("10.0.0.1", "host.example.com"); err != nil {
+		t.Fatalf("SetIPDNS() error = %v", err)
+	}
+	stored, _ := srv.Get("/ipam/ip-addresses", ip.ID)
+	if stored["dns_name"] != "host.example.com" {
+		t.Errorf("dns_name = %v, want host.example.com", stored["dns_name"])
+	}
+}
@@ -0,0 +1,153 @@
+package netbox
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+)
+
+// Option configures optional settings of a Client created by NewClient.
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	timeout     time.Duration
+	tlsConfig   *tls.Config
+	rootCAs     *x509.CertPool
+	clientCerts []tls.Certificate
+	insecure    bool
+	proxyURL    string
+	userAgent   string
+	retry       RetryPolicy
+	list        *ListOptions
+}
+
+// WithHTTPClient makes the Client send requests through a copy of hc
+// instead of a new http.Client.  The other options change the copy and
+// a clone of its *http.Transport, never hc itself.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = hc
+	}
+}
+
+// WithTransport replaces the RoundTripper used to send requests, for
+// example to add tracing or a test transport.  The TLS and proxy options
+// only apply when the transport is an *http.Transport, and are set on a
+// clone of it.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.transport = rt
+	}
+}
+
+// WithTimeout sets the overall time limit for each request attempt.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration.  WithRootCAs,
+// WithClientCertificates and WithInsecureSkipVerify are applied on top
+// of it.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.tlsConfig = config
+	}
+}
+
+// WithRootCAs verifies the Netbox server certificate against pool
+// instead of the system roots.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(o *clientOptions) {
+		o.rootCAs = pool
+	}
+}
+
+// WithClientCertificates presents certs to Netbox for mutual TLS.
+func WithClientCertificates(certs ...tls.Certificate) Option {
+	return func(o *clientOptions) {
+		o.clientCerts = append(o.clientCerts, certs...)
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the Netbox server
+// certificate.  It is intended for lab instances with self-signed
+// certificates only.
+func WithInsecureSkipVerify() Option {
+	return func(o *clientOptions) {
+		o.insecure = true
+	}
+}
+
+// WithProxy sends requests through the given HTTP proxy URL.
+func WithProxy(proxyURL string) Option {
+	return func(o *clientOptions) {
+		o.proxyURL = proxyURL
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for the client.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retry = policy
+	}
+}
+
+// WithListOptions sets the paging used by every list method of the
+// client, such as the page size and the maximum page count.  Listings
+// given their own ListOptions use those instead.
+func WithListOptions(opts ListOptions) Option {
+	return func(o *clientOptions) {
+		o.list = &opts
+	}
+}
+
+// applyOptions configures the resty client from o.
+func (c *Client) applyOptions(o *clientOptions) {
+	if t, ok := o.transport.(*http.Transport); ok {
+		// cloned so the TLS and proxy options leave the caller's alone
+		c.client.SetTransport(t.Clone())
+	} else if o.transport != nil {
+		c.client.SetTransport(o.transport)
+	}
+	if o.timeout > 0 {
+		c.client.SetTimeout(o.timeout)
+	}
+	if o.userAgent != "" {
+		c.client.SetHeader("User-Agent", o.userAgent)
+	}
+	if o.proxyURL != "" {
+		c.client.SetProxy(o.proxyURL)
+	}
+	if o.tlsConfig != nil || o.rootCAs != nil || len(o.clientCerts) > 0 || o.insecure {
+		config := &tls.Config{}
+		if o.tlsConfig != nil {
+			config = o.tlsConfig.Clone()
+		}
+		if o.rootCAs != nil {
+			config.RootCAs = o.rootCAs
+		}
+		config.Certificates = append(config.Certificates, o.clientCerts...)
+		if o.insecure {
+			config.InsecureSkipVerify = true
+		}
+		if _, err := c.client.Transport(); err != nil {
+			c.log.Warn("TLS options ignored", "error", err)
+		} else {
+			c.client.SetTLSClientConfig(config)
+		}
+	}
+	c.SetRetryPolicy(o.retry)
+	c.listOpts = o.list
+}
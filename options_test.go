@@ -0,0 +1,100 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+type countingTransport struct {
+	calls int
+	next  http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.calls++
+	return t.next.RoundTrip(r)
+}
+
+func TestNewClientOptions(t *testing.T) {
+	var userAgent string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 0, "results": []}`))
+	}))
+	defer srv.Close()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	pool := srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	c := NewClient(srv.URL, "token", logger, WithRootCAs(pool), WithUserAgent("netbox-sync/1.0"))
+	if err := c.Search("device", &DeviceVMSearchResults{}); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if userAgent != "netbox-sync/1.0" {
+		t.Errorf("User-Agent = %q, want %q", userAgent, "netbox-sync/1.0")
+	}
+
+	transport := &countingTransport{next: srv.Client().Transport}
+	c = NewClient(srv.URL, "token", logger, WithTransport(transport))
+	if err := c.Search("device", &DeviceVMSearchResults{}); err != nil {
+		t.Fatalf("Search() with custom transport error = %v", err)
+	}
+	if transport.calls != 1 {
+		t.Errorf("custom transport used %d times, want 1", transport.calls)
+	}
+
+	c = NewClient(srv.URL, "token", logger, WithInsecureSkipVerify())
+	if err := c.Search("device", &DeviceVMSearchResults{}); err != nil {
+		t.Errorf("Search() with InsecureSkipVerify error = %v", err)
+	}
+}
+
+func TestNewClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+	c := NewClient(srv.URL, "token", slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithTimeout(20*time.Millisecond),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+	)
+	err := c.SearchCtx(context.Background(), "device", &DeviceVMSearchResults{})
+	var timeout interface{ Timeout() bool }
+	if !errors.As(err, &timeout) || !timeout.Timeout() {
+		t.Errorf("Search() error = %v, want timeout", err)
+	}
+}
+
+func TestNewClientLeavesHTTPClientAlone(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	transport := &http.Transport{}
+	hc := &http.Client{Transport: transport}
+	NewClient("https://netbox.example.com", "token", logger,
+		WithHTTPClient(hc),
+		WithTimeout(time.Second),
+		WithProxy("http://proxy.example.com:3128"),
+		WithInsecureSkipVerify(),
+	)
+	if hc.Timeout != 0 || hc.CheckRedirect != nil || hc.Transport != transport {
+		t.Errorf("WithHTTPClient() changed the client: %+v", hc)
+	}
+	// Clone sets up HTTP/2 on the original, so only our settings are checked
+	if transport.Proxy != nil || (transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify) {
+		t.Errorf("WithHTTPClient() changed the transport proxy or TLS config")
+	}
+
+	shared := &http.Transport{}
+	NewClient("https://netbox.example.com", "token", logger, WithTransport(shared), WithInsecureSkipVerify())
+	if shared.TLSClientConfig != nil && shared.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("WithTransport() changed the transport TLS config")
+	}
+}
@@ -0,0 +1,118 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ErrPaginationLoop is returned when Netbox sends a next URL that has
+// already been retrieved during the same listing.
+var ErrPaginationLoop = errors.New("netbox returned a next page that was already retrieved")
+
+// ErrTooManyPages is returned when a listing needs more pages than
+// ListOptions.MaxPages allows.
+var ErrTooManyPages = errors.New("maximum page count exceeded")
+
+// Page is a single page of results returned by a Netbox list endpoint.
+type Page[T any] struct {
+	Count    int     `json:"count"`
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+	Results  []T     `json:"results"`
+}
+
+// ListOptions controls how list endpoints are paged.
+type ListOptions struct {
+	// Limit is the number of results requested per page.  Zero uses
+	// the Netbox default (PAGINATE_COUNT, normally 50).
+	Limit int
+	// MaxPages stops the listing with ErrTooManyPages once this many pages
+	// have been retrieved.  Zero means no limit.
+	MaxPages int
+}
+
+// Paginate retrieves the list at pageURL and follows the Next link of each
+// page, calling yield for every result in order.  Only one page is held in
+// memory at a time, and each page is decoded into a fresh Page so nothing
+// carries over from the previous one.  If yield returns false paging stops
+// and Paginate returns nil.  A nil opts uses the client's WithListOptions.
+func Paginate[T any](ctx context.Context, c *Client, pageURL string, opts *ListOptions, yield func(T) bool) error {
+	if opts == nil {
+		opts = c.listOpts
+	}
+	if opts != nil && opts.Limit > 0 {
+		limited, err := setQueryParam(pageURL, "limit", strconv.Itoa(opts.Limit))
+		if err != nil {
+			return err
+		}
+		pageURL = limited
+	}
+	seen := make(map[string]bool)
+	next := &pageURL
+	for next != nil {
+		if seen[*next] {
+			c.log.Error("pagination loop detected", "url", *next)
+			return fmt.Errorf("%w: %s", ErrPaginationLoop, *next)
+		}
+		if opts != nil && opts.MaxPages > 0 && len(seen) >= opts.MaxPages {
+			c.log.Error("maximum page count exceeded", "url", pageURL, "pages", opts.MaxPages)
+			return fmt.Errorf("%w: %d", ErrTooManyPages, opts.MaxPages)
+		}
+		seen[*next] = true
+		page := Page[T]{}
+		r := c.buildRequest(ctx).SetResult(&page)
+		resp, err := r.Get(*next)
+		if err != nil {
+			c.log.Error(fmt.Sprintf("error searching %s", r.URL), "err", err)
+			return err
+		}
+		if err = checkStatus(resp); err != nil {
+			c.log.Error(fmt.Sprintf("%d searching %s", resp.StatusCode(), r.URL), "err", err)
+			return err
+		}
+		for _, item := range page.Results {
+			if !yield(item) {
+				return nil
+			}
+		}
+		next = page.Next
+	}
+	return nil
+}
+
+// List streams every object of the given model that matches args to yield.
+// Args should be specified as key=value (eg. has_primary_ip=true).  See
+// Paginate for how yield controls iteration.
+func List[T any](ctx context.Context, c *Client, objectType string, opts *ListOptions, yield func(T) bool, args ...string) error {
+	m, err := LookupModel(objectType)
+	if err != nil {
+		c.log.Error("unknown model", "model", objectType, "error", err)
+		return err
+	}
+	return Paginate(ctx, c, c.buildURL(m.Path+"/?%s", buildQueryPath(args...)), opts, yield)
+}
+
+// listAll collects every result of List into a slice.
+func listAll[T any](ctx context.Context, c *Client, objectType string, args ...string) ([]T, error) {
+	var results []T
+	err := List(ctx, c, objectType, nil, func(item T) bool {
+		results = append(results, item)
+		return true
+	}, args...)
+	return results, err
+}
+
+// setQueryParam replaces the value of key in the query string of rawURL.
+func setQueryParam(rawURL string, key string, value string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL, err
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
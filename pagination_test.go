@@ -0,0 +1,136 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type pageItem struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// pagingServer serves the given JSON bodies as ?page=1, ?page=2, ...
+// The {base} marker in each body is replaced with the server's base URL.
+func pagingServer(t *testing.T, pages ...string) (*Client, *[]string) {
+	t.Helper()
+	var requested []string
+	var base string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RawQuery)
+		var n int
+		fmt.Sscan(r.URL.Query().Get("page"), &n)
+		if n == 0 {
+			n = 1
+		}
+		if n > len(pages) {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, strings.ReplaceAll(pages[n-1], "{base}", base))
+	}))
+	base = c.baseURL
+	return c, &requested
+}
+
+func collectPages(c *Client, opts *ListOptions, stopAfter int) ([]pageItem, error) {
+	var items []pageItem
+	err := Paginate(context.Background(), c, c.buildURL("/dcim/devices/?page=1"), opts, func(item pageItem) bool {
+		items = append(items, item)
+		return stopAfter == 0 || len(items) < stopAfter
+	})
+	return items, err
+}
+
+func TestPaginateFreshPages(t *testing.T) {
+	c, _ := pagingServer(t,
+		`{"count": 3, "next": "{base}/api/dcim/devices/?page=2", "results": [{"name": "a", "description": "first"}, {"name": "b", "description": "second"}]}`,
+		`{"count": 3, "results": [{"name": "c"}]}`,
+	)
+	items, err := collectPages(c, nil, 0)
+	if err != nil {
+		t.Fatalf("Paginate() error = %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("Paginate() returned %d items, want 3", len(items))
+	}
+	if items[2].Description != "" {
+		t.Errorf("page 2 item inherited description %q from page 1", items[2].Description)
+	}
+}
+
+func TestPaginateLoop(t *testing.T) {
+	c, requested := pagingServer(t,
+		`{"count": 4, "next": "{base}/api/dcim/devices/?page=2", "results": [{"name": "a"}]}`,
+		`{"count": 4, "next": "{base}/api/dcim/devices/?page=2", "results": [{"name": "b"}]}`,
+	)
+	_, err := collectPages(c, nil, 0)
+	if !errors.Is(err, ErrPaginationLoop) {
+		t.Errorf("Paginate() error = %v, want %v", err, ErrPaginationLoop)
+	}
+	if len(*requested) != 2 {
+		t.Errorf("Paginate() made %d requests, want 2", len(*requested))
+	}
+}
+
+func TestPaginateMaxPages(t *testing.T) {
+	c, _ := pagingServer(t,
+		`{"count": 3, "next": "{base}/api/dcim/devices/?page=2", "results": [{"name": "a"}]}`,
+		`{"count": 3, "next": "{base}/api/dcim/devices/?page=3", "results": [{"name": "b"}]}`,
+		`{"count": 3, "results": [{"name": "c"}]}`,
+	)
+	items, err := collectPages(c, &ListOptions{MaxPages: 2}, 0)
+	if !errors.Is(err, ErrTooManyPages) {
+		t.Errorf("Paginate() error = %v, want %v", err, ErrTooManyPages)
+	}
+	if len(items) != 2 {
+		t.Errorf("Paginate() returned %d items, want 2", len(items))
+	}
+}
+
+func TestPaginateLimitAndEarlyStop(t *testing.T) {
+	c, requested := pagingServer(t,
+		`{"count": 3, "next": "{base}/api/dcim/devices/?page=2", "results": [{"name": "a"}, {"name": "b"}]}`,
+		`{"count": 3, "results": [{"name": "c"}]}`,
+	)
+	items, err := collectPages(c, &ListOptions{Limit: 2}, 1)
+	if err != nil {
+		t.Fatalf("Paginate() error = %v", err)
+	}
+	if len(items) != 1 || len(*requested) != 1 {
+		t.Errorf("Paginate() returned %d items in %d requests, want 1 in 1", len(items), len(*requested))
+	}
+	if (*requested)[0] != "limit=2&page=1" {
+		t.Errorf("Paginate() requested %q, want %q", (*requested)[0], "limit=2&page=1")
+	}
+}
+
+func TestClientListOptions(t *testing.T) {
+	var requested []string
+	var base string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"count": 9, "next": "%s/api/tenancy/tenants/?page=%d", "results": [{"id": %d}]}`, base, len(requested)+1, len(requested))
+	}), WithListOptions(ListOptions{Limit: 1, MaxPages: 3}))
+	base = c.baseURL
+
+	tenants, err := c.ListTenants(nil)
+	if !errors.Is(err, ErrTooManyPages) {
+		t.Errorf("ListTenants() error = %v, want %v", err, ErrTooManyPages)
+	}
+	if len(tenants) != 3 || len(requested) != 3 || !strings.Contains(requested[0], "limit=1") {
+		t.Errorf("ListTenants() = %d tenants in requests %q, want 3 with limit=1", len(tenants), requested)
+	}
+
+	requested = nil
+	err = Paginate(context.Background(), c, c.buildURL("/tenancy/tenants/"), &ListOptions{MaxPages: 2}, func(Tenant) bool { return true })
+	if !errors.Is(err, ErrTooManyPages) || len(requested) != 2 {
+		t.Errorf("Paginate() with its own options made %d requests, %v, want 2", len(requested), err)
+	}
+}
@@ -0,0 +1,455 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// Rack faces
+const (
+	RackFront = "front"
+	RackRear  = "rear"
+)
+
+type Rack struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Name         string                 `json:"name"`
+	FacilityID   *string                `json:"facility_id"`
+	Site         DisplayIDName          `json:"site"`
+	Location     *DisplayIDName         `json:"location"`
+	Tenant       *DisplayIDName         `json:"tenant"`
+	Status       LabelValue             `json:"status"`
+	Role         *DisplayIDName         `json:"role"`
+	Serial       string                 `json:"serial"`
+	AssetTag     *string                `json:"asset_tag"`
+	Type         *LabelValue            `json:"type"`
+	UHeight      int                    `json:"u_height"`
+	StartingUnit int                    `json:"starting_unit"`
+	DescUnits    bool                   `json:"desc_units"`
+	OuterWidth   *int                   `json:"outer_width"`
+	OuterDepth   *int                   `json:"outer_depth"`
+	OuterUnit    *LabelValue            `json:"outer_unit"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	DeviceCount  int                    `json:"device_count"`
+}
+
+// RackEdit is used to add/update a rack.  Width is the rail width in
+// inches: 10, 19, 21 or 23.
+type RackEdit struct {
+	Name         string                 `json:"name,omitempty"`
+	FacilityID   *string                `json:"facility_id,omitempty"`
+	Site         int                    `json:"site,omitempty"`
+	Location     *int                   `json:"location,omitempty"`
+	Tenant       *int                   `json:"tenant,omitempty"`
+	Status       *string                `json:"status,omitempty"`
+	Role         *int                   `json:"role,omitempty"`
+	Serial       *string                `json:"serial,omitempty"`
+	AssetTag     *string                `json:"asset_tag,omitempty"`
+	Type         *string                `json:"type,omitempty"`
+	Width        *int                   `json:"width,omitempty"`
+	UHeight      *int                   `json:"u_height,omitempty"`
+	StartingUnit *int                   `json:"starting_unit,omitempty"`
+	DescUnits    *bool                  `json:"desc_units,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type RackRole struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Name         string                 `json:"name"`
+	Slug         string                 `json:"slug"`
+	Color        string                 `json:"color"`
+	Description  string                 `json:"description"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	RackCount    int                    `json:"rack_count"`
+}
+
+// RackRoleEdit is used to add/update a rack role.  Color is a hex RGB
+// value without the leading #, eg. "9e9e9e".
+type RackRoleEdit struct {
+	Name         string                 `json:"name,omitempty"`
+	Slug         string                 `json:"slug,omitempty"`
+	Color        string                 `json:"color,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// NestedUser is a Netbox user as nested in other objects
+type NestedUser struct {
+	ID       int    `json:"id"`
+	URL      string `json:"url"`
+	Display  string `json:"display"`
+	Username string `json:"username"`
+}
+
+type RackReservation struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Rack         DisplayIDName          `json:"rack"`
+	Units        []int                  `json:"units"`
+	User         *NestedUser            `json:"user"`
+	Tenant       *DisplayIDName         `json:"tenant"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	Created      string                 `json:"created"`
+}
+
+// RackReservationEdit is used to add/update a rack reservation.  Netbox
+// requires the user the units are reserved for and a description.
+type RackReservationEdit struct {
+	Rack         int                    `json:"rack,omitempty"`
+	Units        []int                  `json:"units,omitempty"`
+	User         int                    `json:"user,omitempty"`
+	Tenant       *int                   `json:"tenant,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// RackUnit is a unit of a rack elevation
+type RackUnit struct {
+	ID       float64        `json:"id"`
+	Name     string         `json:"name"`
+	Face     LabelValue     `json:"face"`
+	Device   *DisplayIDName `json:"device"`
+	Occupied bool           `json:"occupied"`
+}
+
+// RackElevation is one face of a rack.  Units are in the order Netbox
+// returns them, Occupied and Free hold the unit numbers in ascending
+// order.
+type RackElevation struct {
+	Face     string
+	Units    []RackUnit
+	Occupied []float64
+	Free     []float64
+}
+
+// ListRacks returns all racks that match the filter.  Filter
+// needs to be given as a valid api filter (eg. site_id=1)
+func (c *Client) ListRacks(filter *string) ([]Rack, error) {
+	return c.ListRacksCtx(context.Background(), filter)
+}
+
+// ListRacksCtx is like ListRacks but uses ctx for the requests.
+func (c *Client) ListRacksCtx(ctx context.Context, filter *string) ([]Rack, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	racks, err := listAll[Rack](ctx, c, "rack", args)
+	if err != nil {
+		c.log.Error("error finding racks", "filter", filter, "error", err)
+	}
+	return racks, err
+}
+
+// GetRack retrieves the rack with the given ID
+func (c *Client) GetRack(id int) (Rack, error) {
+	return c.GetRackCtx(context.Background(), id)
+}
+
+// GetRackCtx is like GetRack but uses ctx for the request.
+func (c *Client) GetRackCtx(ctx context.Context, id int) (Rack, error) {
+	return getObject[Rack](ctx, c, "rack", id)
+}
+
+// FindRack looks up the rack by name within the site
+func (c *Client) FindRack(siteID int, name string) (Rack, error) {
+	return c.FindRackCtx(context.Background(), siteID, name)
+}
+
+// FindRackCtx is like FindRack but uses ctx for the request.
+func (c *Client) FindRackCtx(ctx context.Context, siteID int, name string) (Rack, error) {
+	return findOne[Rack](ctx, c, "rack", fmt.Sprintf("site_id=%d", siteID), fmt.Sprintf("name=%s", url.QueryEscape(name)))
+}
+
+// AddRack creates a new rack
+func (c *Client) AddRack(rack RackEdit) (Rack, error) {
+	return c.AddRackCtx(context.Background(), rack)
+}
+
+// AddRackCtx is like AddRack but uses ctx for the request.
+func (c *Client) AddRackCtx(ctx context.Context, rack RackEdit) (Rack, error) {
+	r, err := createObject[Rack](ctx, c, "rack", rack)
+	if err != nil {
+		c.log.Error("could not create rack", "name", rack.Name, "error", err)
+		return r, err
+	}
+	c.log.Info("add rack", "name", r.Name, "id", r.ID)
+	return r, nil
+}
+
+// UpdateRack modifies the given fields of the rack
+func (c *Client) UpdateRack(id int, rack RackEdit) (Rack, error) {
+	return c.UpdateRackCtx(context.Background(), id, rack)
+}
+
+// UpdateRackCtx is like UpdateRack but uses ctx for the request.
+func (c *Client) UpdateRackCtx(ctx context.Context, id int, rack RackEdit) (Rack, error) {
+	return updateObject[Rack](ctx, c, "rack", id, rack)
+}
+
+// DeleteRack removes the rack from Netbox
+func (c *Client) DeleteRack(id int) error {
+	return c.DeleteRackCtx(context.Background(), id)
+}
+
+// DeleteRackCtx is like DeleteRack but uses ctx for the request.
+func (c *Client) DeleteRackCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "rack", id)
+}
+
+// ListRackRoles returns all rack roles that match the filter.  Filter
+// needs to be given as a valid api filter (eg. name=Network)
+func (c *Client) ListRackRoles(filter *string) ([]RackRole, error) {
+	return c.ListRackRolesCtx(context.Background(), filter)
+}
+
+// ListRackRolesCtx is like ListRackRoles but uses ctx for the requests.
+func (c *Client) ListRackRolesCtx(ctx context.Context, filter *string) ([]RackRole, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	roles, err := listAll[RackRole](ctx, c, "rack-role", args)
+	if err != nil {
+		c.log.Error("error finding rack roles", "filter", filter, "error", err)
+	}
+	return roles, err
+}
+
+// GetRackRole looks up the rack role by name
+func (c *Client) GetRackRole(name string) (RackRole, error) {
+	return c.GetRackRoleCtx(context.Background(), name)
+}
+
+// GetRackRoleCtx is like GetRackRole but uses ctx for the request.
+func (c *Client) GetRackRoleCtx(ctx context.Context, name string) (RackRole, error) {
+	return findOne[RackRole](ctx, c, "rack-role", fmt.Sprintf("slug=%s", Slugify(name)))
+}
+
+// AddRackRole creates a new rack role.  The color defaults to grey.
+func (c *Client) AddRackRole(role RackRoleEdit) (RackRole, error) {
+	return c.AddRackRoleCtx(context.Background(), role)
+}
+
+// AddRackRoleCtx is like AddRackRole but uses ctx for the request.
+func (c *Client) AddRackRoleCtx(ctx context.Context, role RackRoleEdit) (RackRole, error) {
+	if role.Slug == "" {
+		role.Slug = Slugify(role.Name)
+	}
+	if role.Color == "" {
+		role.Color = defaultRoleColor
+	}
+	return createObject[RackRole](ctx, c, "rack-role", role)
+}
+
+// GetOrAddRackRole will retrieve the requested rack role
+// by name and add it if it does not exist
+func (c *Client) GetOrAddRackRole(name string) (RackRole, error) {
+	return c.GetOrAddRackRoleCtx(context.Background(), name)
+}
+
+// GetOrAddRackRoleCtx is like GetOrAddRackRole but uses ctx for the requests.
+func (c *Client) GetOrAddRackRoleCtx(ctx context.Context, name string) (RackRole, error) {
+	role, err := c.GetRackRoleCtx(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddRackRoleCtx(ctx, RackRoleEdit{Name: name})
+	}
+	return role, err
+}
+
+// UpdateRackRole modifies the given fields of the rack role
+func (c *Client) UpdateRackRole(id int, role RackRoleEdit) (RackRole, error) {
+	return c.UpdateRackRoleCtx(context.Background(), id, role)
+}
+
+// UpdateRackRoleCtx is like UpdateRackRole but uses ctx for the request.
+func (c *Client) UpdateRackRoleCtx(ctx context.Context, id int, role RackRoleEdit) (RackRole, error) {
+	return updateObject[RackRole](ctx, c, "rack-role", id, role)
+}
+
+// DeleteRackRole removes the rack role from Netbox
+func (c *Client) DeleteRackRole(id int) error {
+	return c.DeleteRackRoleCtx(context.Background(), id)
+}
+
+// DeleteRackRoleCtx is like DeleteRackRole but uses ctx for the request.
+func (c *Client) DeleteRackRoleCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "rack-role", id)
+}
+
+// ListRackReservations returns all reservations that match the filter.
+// Filter needs to be given as a valid api filter (eg. rack_id=1)
+func (c *Client) ListRackReservations(filter *string) ([]RackReservation, error) {
+	return c.ListRackReservationsCtx(context.Background(), filter)
+}
+
+// ListRackReservationsCtx is like ListRackReservations but uses ctx for the requests.
+func (c *Client) ListRackReservationsCtx(ctx context.Context, filter *string) ([]RackReservation, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	reservations, err := listAll[RackReservation](ctx, c, "rack-reservation", args)
+	if err != nil {
+		c.log.Error("error finding rack reservations", "filter", filter, "error", err)
+	}
+	return reservations, err
+}
+
+// GetRackReservation retrieves the reservation with the given ID
+func (c *Client) GetRackReservation(id int) (RackReservation, error) {
+	return c.GetRackReservationCtx(context.Background(), id)
+}
+
+// GetRackReservationCtx is like GetRackReservation but uses ctx for the request.
+func (c *Client) GetRackReservationCtx(ctx context.Context, id int) (RackReservation, error) {
+	return getObject[RackReservation](ctx, c, "rack-reservation", id)
+}
+
+// AddRackReservation reserves units of a rack
+func (c *Client) AddRackReservation(reservation RackReservationEdit) (RackReservation, error) {
+	return c.AddRackReservationCtx(context.Background(), reservation)
+}
+
+// AddRackReservationCtx is like AddRackReservation but uses ctx for the request.
+func (c *Client) AddRackReservationCtx(ctx context.Context, reservation RackReservationEdit) (RackReservation, error) {
+	r, err := createObject[RackReservation](ctx, c, "rack-reservation", reservation)
+	if err != nil {
+		c.log.Error("could not reserve units", "rack", reservation.Rack, "units", reservation.Units, "error", err)
+		return r, err
+	}
+	c.log.Info("reserve units", "rack", reservation.Rack, "units", reservation.Units, "id", r.ID)
+	return r, nil
+}
+
+// UpdateRackReservation modifies the given fields of the reservation
+func (c *Client) UpdateRackReservation(id int, reservation RackReservationEdit) (RackReservation, error) {
+	return c.UpdateRackReservationCtx(context.Background(), id, reservation)
+}
+
+// UpdateRackReservationCtx is like UpdateRackReservation but uses ctx for the request.
+func (c *Client) UpdateRackReservationCtx(ctx context.Context, id int, reservation RackReservationEdit) (RackReservation, error) {
+	return updateObject[RackReservation](ctx, c, "rack-reservation", id, reservation)
+}
+
+// DeleteRackReservation removes the reservation, freeing its units
+func (c *Client) DeleteRackReservation(id int) error {
+	return c.DeleteRackReservationCtx(context.Background(), id)
+}
+
+// DeleteRackReservationCtx is like DeleteRackReservation but uses ctx for the request.
+func (c *Client) DeleteRackReservationCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "rack-reservation", id)
+}
+
+// GetRackElevation returns the units of the given face of the rack,
+// RackFront or RackRear, and which of them are occupied by devices.
+// Full depth devices occupy their units on both faces.
+func (c *Client) GetRackElevation(rackID int, face string) (RackElevation, error) {
+	return c.GetRackElevationCtx(context.Background(), rackID, face)
+}
+
+// GetRackElevationCtx is like GetRackElevation but uses ctx for the requests.
+func (c *Client) GetRackElevationCtx(ctx context.Context, rackID int, face string) (RackElevation, error) {
+	elevation := RackElevation{Face: face}
+	if face != RackFront && face != RackRear {
+		return elevation, fmt.Errorf("invalid rack face %q", face)
+	}
+	rackURL, err := c.modelURL(OpGet, "rack", rackID)
+	if err != nil {
+		return elevation, err
+	}
+	err = Paginate(ctx, c, rackURL+"elevation/?face="+face, nil, func(unit RackUnit) bool {
+		elevation.Units = append(elevation.Units, unit)
+		if unit.Occupied || unit.Device != nil {
+			elevation.Occupied = append(elevation.Occupied, unit.ID)
+		} else {
+			elevation.Free = append(elevation.Free, unit.ID)
+		}
+		return true
+	})
+	if err != nil {
+		c.log.Error("error getting rack elevation", "rack", rackID, "face", face, "error", err)
+		return elevation, err
+	}
+	sort.Float64s(elevation.Occupied)
+	sort.Float64s(elevation.Free)
+	return elevation, nil
+}
+
+// FindFreeUnits returns the lowest unit of every position in the rack
+// where a device of the given height fits, in ascending order.  A unit
+// is free if no device occupies it on either face and it is not
+// reserved, so the positions suit full depth devices.  The lowest unit
+// is the position to give NewDevice.
+func (c *Client) FindFreeUnits(rackID int, height int) ([]float64, error) {
+	return c.FindFreeUnitsCtx(context.Background(), rackID, height)
+}
+
+// FindFreeUnitsCtx is like FindFreeUnits but uses ctx for the requests.
+func (c *Client) FindFreeUnitsCtx(ctx context.Context, rackID int, height int) ([]float64, error) {
+	if height < 1 {
+		return nil, fmt.Errorf("invalid device height %d", height)
+	}
+	front, err := c.GetRackElevationCtx(ctx, rackID, RackFront)
+	if err != nil {
+		return nil, err
+	}
+	rear, err := c.GetRackElevationCtx(ctx, rackID, RackRear)
+	if err != nil {
+		return nil, err
+	}
+	reservations, err := listAll[RackReservation](ctx, c, "rack-reservation", fmt.Sprintf("rack_id=%d", rackID))
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[float64]bool)
+	for _, u := range append(front.Occupied, rear.Occupied...) {
+		used[u] = true
+	}
+	for _, reservation := range reservations {
+		for _, u := range reservation.Units {
+			used[float64(u)] = true
+		}
+	}
+
+	// free runs of units are counted from the top down, so the length
+	// of the run starting at each unit is known when it is reached
+	units := front.Free
+	run := make(map[float64]int)
+	var positions []float64
+	for i := len(units) - 1; i >= 0; i-- {
+		u := units[i]
+		if used[u] {
+			continue
+		}
+		run[u] = run[u+1] + 1
+		if run[u] >= height {
+			positions = append(positions, u)
+		}
+	}
+	sort.Float64s(positions)
+	return positions, nil
+}
@@ -0,0 +1,100 @@
+package netbox_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestRacks(t *testing.T) {
+	srv, c := newClient(t)
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	maker := srv.Add("/dcim/manufacturers", netboxtest.Object{"name": "Juniper", "slug": "juniper"})
+	srv.Add("/dcim/device-types", netboxtest.Object{"manufacturer": maker["id"], "model": "MX204", "slug": "mx204", "u_height": 2, "is_full_depth": true})
+	srv.Add("/dcim/device-types", netboxtest.Object{"manufacturer": maker["id"], "model": "Patch Panel", "slug": "patch-panel", "u_height": 1, "is_full_depth": false})
+	srv.Add("/dcim/device-roles", netboxtest.Object{"name": "Router", "slug": "router"})
+	user := srv.Add("/users/users", netboxtest.Object{"username": "planner"})
+
+	role, err := c.GetOrAddRackRole("Network")
+	if err != nil {
+		t.Fatalf("GetOrAddRackRole() error = %v", err)
+	}
+	if again, err := c.GetOrAddRackRole("network"); err != nil || again.ID != role.ID {
+		t.Errorf("GetOrAddRackRole() again = %d, %v, want %d", again.ID, err, role.ID)
+	}
+	height, status := 10, "active"
+	rack, err := c.AddRack(netbox.RackEdit{Name: "R1", Site: site["id"].(int), Role: &role.ID, UHeight: &height, Status: &status})
+	if err != nil {
+		t.Fatalf("AddRack() error = %v", err)
+	}
+	if rack.Role == nil || rack.Role.ID != role.ID || rack.UHeight != 10 || rack.Status.Value != "active" {
+		t.Errorf("AddRack() = %+v", rack)
+	}
+	if found, err := c.FindRack(rack.Site.ID, "R1"); err != nil || found.ID != rack.ID {
+		t.Errorf("FindRack() = %d, %v, want %d", found.ID, err, rack.ID)
+	}
+
+	one, five := 1.0, 5.0
+	for _, dev := range []netbox.NewDevice{
+		{Name: "rtr01", DeviceType: "MX204", Role: "Router", Site: "HQ", Rack: "R1", Position: &one, Face: netbox.RackFront},
+		{Name: "pp01", DeviceType: "Patch Panel", Role: "Router", Site: "HQ", Rack: "R1", Position: &five, Face: netbox.RackRear},
+	} {
+		if _, err := c.AddDevice(dev); err != nil {
+			t.Fatalf("AddDevice(%s) error = %v", dev.Name, err)
+		}
+	}
+	reservation, err := c.AddRackReservation(netbox.RackReservationEdit{Rack: rack.ID, Units: []int{8}, User: user["id"].(int), Description: "new firewall"})
+	if err != nil {
+		t.Fatalf("AddRackReservation() error = %v", err)
+	}
+	if reservation.Rack.ID != rack.ID || !reflect.DeepEqual(reservation.Units, []int{8}) || reservation.User == nil || reservation.User.Username != "planner" {
+		t.Errorf("AddRackReservation() = %+v", reservation)
+	}
+
+	front, err := c.GetRackElevation(rack.ID, netbox.RackFront)
+	if err != nil {
+		t.Fatalf("GetRackElevation() error = %v", err)
+	}
+	if len(front.Units) != 10 || !reflect.DeepEqual(front.Occupied, []float64{1, 2}) || front.Units[9].Device == nil || front.Units[9].Device.Name != "rtr01" {
+		t.Errorf("GetRackElevation(front) = %+v", front)
+	}
+	rear, err := c.GetRackElevation(rack.ID, netbox.RackRear)
+	if err != nil || !reflect.DeepEqual(rear.Occupied, []float64{1, 2, 5}) || len(rear.Free) != 7 {
+		t.Errorf("GetRackElevation(rear) = %+v, %v", rear, err)
+	}
+	if _, err := c.GetRackElevation(rack.ID, "top"); err == nil {
+		t.Errorf("GetRackElevation() with an invalid face succeeded")
+	}
+
+	tests := []struct {
+		height int
+		want   []float64
+	}{
+		{1, []float64{3, 4, 6, 7, 9, 10}},
+		{2, []float64{3, 6, 9}},
+		{3, nil},
+	}
+	for _, tt := range tests {
+		got, err := c.FindFreeUnits(rack.ID, tt.height)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindFreeUnits(%d) = %v, %v, want %v", tt.height, got, err, tt.want)
+		}
+	}
+
+	if err := c.DeleteRackReservation(reservation.ID); err != nil {
+		t.Fatalf("DeleteRackReservation() error = %v", err)
+	}
+	if got, err := c.FindFreeUnits(rack.ID, 3); err != nil || !reflect.DeepEqual(got, []float64{6, 7, 8}) {
+		t.Errorf("FindFreeUnits(3) after deleting the reservation = %v, %v, want [6 7 8]", got, err)
+	}
+
+	serial := "SN1"
+	if updated, err := c.UpdateRack(rack.ID, netbox.RackEdit{Serial: &serial}); err != nil || updated.Serial != "SN1" || updated.Name != "R1" {
+		t.Errorf("UpdateRack() = %+v, %v", updated, err)
+	}
+	if err := c.DeleteRackRole(role.ID); err != nil {
+		t.Errorf("DeleteRackRole() error = %v", err)
+	}
+}
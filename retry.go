@@ -0,0 +1,121 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RetryPolicy controls how requests are retried after transient failures:
+// connection errors and 429, 502, 503 or 504 responses.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts for a request, including
+	// the first.  Values below 2 disable retries.
+	MaxAttempts int
+	// WaitTime is the base delay before the first retry.  Later retries
+	// back off exponentially with jitter.
+	WaitTime time.Duration
+	// MaxWaitTime caps the delay between attempts, including delays
+	// requested by a Retry-After header.
+	MaxWaitTime time.Duration
+	// RetryNonIdempotent allows POST and PATCH requests to be retried.  These
+	// may have been applied by Netbox before the failure was seen, so they
+	// are only retried when explicitly enabled.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is the policy used by NewClient unless WithRetryPolicy
+// is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	WaitTime:    500 * time.Millisecond,
+	MaxWaitTime: 30 * time.Second,
+}
+
+// SetRetryPolicy replaces the client's retry policy.  It should be called
+// before the client is used.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) *Client {
+	c.retry = policy
+	retries := policy.MaxAttempts - 1
+	if retries < 0 {
+		retries = 0
+	}
+	c.client.SetRetryCount(retries).
+		SetRetryWaitTime(policy.WaitTime).
+		SetRetryMaxWaitTime(policy.MaxWaitTime)
+	return c
+}
+
+// initRetries installs the retry hooks on the resty client.  They read
+// c.retry on each call so SetRetryPolicy can be called more than once.
+func (c *Client) initRetries() {
+	c.client.AddRetryCondition(c.shouldRetry).
+		AddRetryHook(c.logRetry).
+		SetRetryAfter(retryAfter)
+}
+
+func (c *Client) shouldRetry(resp *resty.Response, err error) bool {
+	if resp == nil || resp.Request == nil {
+		return false
+	}
+	if ctx := resp.Request.Context(); ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if !c.retry.RetryNonIdempotent && !isIdempotent(resp.Request.Method) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// logRetry logs a retry.  resty also runs the hooks after the last
+// attempt, when no retry follows, so that attempt is not logged.
+func (c *Client) logRetry(resp *resty.Response, err error) {
+	if resp.Request.Attempt >= c.retry.MaxAttempts {
+		return
+	}
+	args := []any{"method", resp.Request.Method, "url", resp.Request.URL, "attempt", resp.Request.Attempt}
+	if err != nil {
+		args = append(args, "error", err)
+	} else {
+		args = append(args, "status", resp.StatusCode())
+	}
+	c.log.Warn("retrying netbox request", args...)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter honors the Retry-After header on 429 and 503 responses.  A
+// zero duration tells resty to use its exponential backoff instead.
+func retryAfter(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+	if resp.StatusCode() != http.StatusTooManyRequests && resp.StatusCode() != http.StatusServiceUnavailable {
+		return 0, nil
+	}
+	header := resp.Header().Get("Retry-After")
+	if header == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	if when, err := http.ParseTime(header); err == nil && time.Until(when) > 0 {
+		return time.Until(when), nil
+	}
+	return 0, nil
+}
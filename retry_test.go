@@ -0,0 +1,86 @@
+package netbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		status        int
+		nonIdempotent bool
+		wantCalls     int32
+		wantErr       error
+	}{
+		{name: "GET retried until success", method: http.MethodGet, status: http.StatusBadGateway, wantCalls: 3},
+		{name: "GET with Retry-After", method: http.MethodGet, status: http.StatusTooManyRequests, wantCalls: 3},
+		{name: "POST not retried by default", method: http.MethodPost, status: http.StatusServiceUnavailable, wantCalls: 1, wantErr: ErrServer},
+		{name: "POST retried when enabled", method: http.MethodPost, status: http.StatusServiceUnavailable, nonIdempotent: true, wantCalls: 3},
+		{name: "client errors not retried", method: http.MethodGet, status: http.StatusBadRequest, wantCalls: 1, wantErr: ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) < 3 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{}`))
+			}))
+			c.SetRetryPolicy(RetryPolicy{
+				MaxAttempts:        3,
+				WaitTime:           time.Millisecond,
+				MaxWaitTime:        10 * time.Millisecond,
+				RetryNonIdempotent: tt.nonIdempotent,
+			})
+			r := c.buildRequest(context.Background())
+			resp, err := r.Execute(tt.method, c.buildURL("/dcim/devices/"))
+			if err == nil {
+				err = checkStatus(resp)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, want %v", tt.method, err, tt.wantErr)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("%s made %d calls, want %d", tt.method, calls.Load(), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryLogging(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+	var logs bytes.Buffer
+	c := NewClient(srv.URL, "test-token", slog.New(slog.NewTextHandler(&logs, nil)))
+	c.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, WaitTime: time.Millisecond, MaxWaitTime: 10 * time.Millisecond})
+
+	resp, err := c.buildRequest(context.Background()).Get(c.buildURL("/dcim/devices/"))
+	if err == nil {
+		err = checkStatus(resp)
+	}
+	if !errors.Is(err, ErrServer) || calls.Load() != 3 {
+		t.Fatalf("GET made %d calls, error = %v, want 3 calls and %v", calls.Load(), err, ErrServer)
+	}
+	if got := strings.Count(logs.String(), "retrying netbox request"); got != 2 {
+		t.Errorf("logged %d retries, want 2:\n%s", got, logs.String())
+	}
+}
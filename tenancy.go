@@ -0,0 +1,431 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+type Tenant struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Name         string                 `json:"name"`
+	Slug         string                 `json:"slug"`
+	Group        *DisplayIDName         `json:"group"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// TenantEdit is used to add/update a tenant
+type TenantEdit struct {
+	Name         string                 `json:"name,omitempty"`
+	Slug         string                 `json:"slug,omitempty"`
+	Group        *int                   `json:"group,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type TenantGroup struct {
+	ID          int            `json:"id"`
+	URL         string         `json:"url"`
+	Display     string         `json:"display"`
+	Name        string         `json:"name"`
+	Slug        string         `json:"slug"`
+	Parent      *DisplayIDName `json:"parent"`
+	Description string         `json:"description"`
+	Tags        []Tag          `json:"tags"`
+	TenantCount int            `json:"tenant_count"`
+}
+
+// TenantGroupEdit is used to add/update a tenant group
+type TenantGroupEdit struct {
+	Name        string  `json:"name,omitempty"`
+	Slug        string  `json:"slug,omitempty"`
+	Parent      *int    `json:"parent,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Tags        []Tag   `json:"tags,omitempty"`
+}
+
+type Contact struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Group        *DisplayIDName         `json:"group"`
+	Name         string                 `json:"name"`
+	Title        string                 `json:"title"`
+	Phone        string                 `json:"phone"`
+	Email        string                 `json:"email"`
+	Address      string                 `json:"address"`
+	Link         string                 `json:"link"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// ContactEdit is used to add/update a contact
+type ContactEdit struct {
+	Group        *int                   `json:"group,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Title        *string                `json:"title,omitempty"`
+	Phone        *string                `json:"phone,omitempty"`
+	Email        *string                `json:"email,omitempty"`
+	Address      *string                `json:"address,omitempty"`
+	Link         *string                `json:"link,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type ContactRole struct {
+	ID          int    `json:"id"`
+	URL         string `json:"url"`
+	Display     string `json:"display"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+}
+
+// ContactAssignment links a contact, in a role, to another object such
+// as a site or tenant.
+type ContactAssignment struct {
+	ID          int           `json:"id"`
+	URL         string        `json:"url"`
+	Display     string        `json:"display"`
+	ContentType string        `json:"content_type"`
+	ObjectType  string        `json:"object_type"`
+	ObjectID    int           `json:"object_id"`
+	Contact     DisplayIDName `json:"contact"`
+	Role        DisplayIDName `json:"role"`
+	Priority    *LabelValue   `json:"priority"`
+}
+
+// GetOrAddTenant retrieves the named tenant, or creates it if
+// it does not exist
+func (c *Client) GetOrAddTenant(name string) (*Tenant, error) {
+	return c.GetOrAddTenantCtx(context.Background(), name)
+}
+
+// GetOrAddTenantCtx is like GetOrAddTenant but uses ctx for the requests.
+func (c *Client) GetOrAddTenantCtx(ctx context.Context, name string) (*Tenant, error) {
+	tenant, err := findOne[Tenant](ctx, c, "tenant", fmt.Sprintf("name=%s", url.QueryEscape(name)))
+	if err == nil {
+		return &tenant, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		c.log.Error("error searching tenants", "tenant", name, "error", err)
+		return nil, err
+	}
+	return c.addTenant(ctx, TenantEdit{
+		Name: name,
+		Slug: Slugify(name),
+		Tags: []Tag{apcTag, customerTag, jobberTag},
+	})
+}
+
+// addTenant creates the tenant in the customer tenant group
+func (c *Client) addTenant(ctx context.Context, tenant TenantEdit) (*Tenant, error) {
+	group, err := c.GetOrAddTenantGroupCtx(ctx, customerGroup.Name)
+	if err != nil {
+		c.log.Error("error finding tenant group", "group", customerGroup.Name, "error", err)
+		return nil, err
+	}
+	tenant.Group = &group.ID
+	return c.AddTenantCtx(ctx, tenant)
+}
+
+// GetTenant retrieves the tenant with the given ID
+func (c *Client) GetTenant(id int) (*Tenant, error) {
+	return c.GetTenantCtx(context.Background(), id)
+}
+
+// GetTenantCtx is like GetTenant but uses ctx for the request.
+func (c *Client) GetTenantCtx(ctx context.Context, id int) (*Tenant, error) {
+	c.log.Debug("getting tenant", "id", id)
+	tenant, err := getObject[Tenant](ctx, c, "tenant", id)
+	if err != nil {
+		c.log.Error("error getting tenant", "id", id, "error", err)
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// ListTenants returns all tenants that match the filter.  Filter
+// needs to be given as a valid api filter (eg. group_id=1)
+func (c *Client) ListTenants(filter *string) ([]Tenant, error) {
+	return c.ListTenantsCtx(context.Background(), filter)
+}
+
+// ListTenantsCtx is like ListTenants but uses ctx for the requests.
+func (c *Client) ListTenantsCtx(ctx context.Context, filter *string) ([]Tenant, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	tenants, err := listAll[Tenant](ctx, c, "tenant", args)
+	if err != nil {
+		c.log.Error("error finding tenants", "filter", filter, "error", err)
+	}
+	return tenants, err
+}
+
+// AddTenant creates a new tenant
+func (c *Client) AddTenant(tenant TenantEdit) (*Tenant, error) {
+	return c.AddTenantCtx(context.Background(), tenant)
+}
+
+// AddTenantCtx is like AddTenant but uses ctx for the request.
+func (c *Client) AddTenantCtx(ctx context.Context, tenant TenantEdit) (*Tenant, error) {
+	if tenant.Slug == "" {
+		tenant.Slug = Slugify(tenant.Name)
+	}
+	newTenant, err := createObject[Tenant](ctx, c, "tenant", tenant)
+	if err != nil {
+		c.log.Error("error adding tenant", "tenant", tenant.Name, "error", err)
+		return nil, err
+	}
+	c.log.Info("added tenant", "id", newTenant.ID, "tenant", newTenant.Name)
+	return &newTenant, nil
+}
+
+// UpdateTenant modifies the given fields of the tenant
+func (c *Client) UpdateTenant(id int, tenant TenantEdit) (*Tenant, error) {
+	return c.UpdateTenantCtx(context.Background(), id, tenant)
+}
+
+// UpdateTenantCtx is like UpdateTenant but uses ctx for the request.
+func (c *Client) UpdateTenantCtx(ctx context.Context, id int, tenant TenantEdit) (*Tenant, error) {
+	updated, err := updateObject[Tenant](ctx, c, "tenant", id, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteTenant removes the tenant from Netbox
+func (c *Client) DeleteTenant(id int) error {
+	return c.DeleteTenantCtx(context.Background(), id)
+}
+
+// DeleteTenantCtx is like DeleteTenant but uses ctx for the request.
+func (c *Client) DeleteTenantCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "tenant", id)
+}
+
+// ListTenantGroups returns all tenant groups that match the filter.
+// Filter needs to be given as a valid api filter (eg. parent_id=1)
+func (c *Client) ListTenantGroups(filter *string) ([]TenantGroup, error) {
+	return c.ListTenantGroupsCtx(context.Background(), filter)
+}
+
+// ListTenantGroupsCtx is like ListTenantGroups but uses ctx for the requests.
+func (c *Client) ListTenantGroupsCtx(ctx context.Context, filter *string) ([]TenantGroup, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	groups, err := listAll[TenantGroup](ctx, c, "tenant-group", args)
+	if err != nil {
+		c.log.Error("error finding tenant groups", "filter", filter, "error", err)
+	}
+	return groups, err
+}
+
+// GetTenantGroup looks up the tenant group by name
+func (c *Client) GetTenantGroup(name string) (TenantGroup, error) {
+	return c.GetTenantGroupCtx(context.Background(), name)
+}
+
+// GetTenantGroupCtx is like GetTenantGroup but uses ctx for the request.
+func (c *Client) GetTenantGroupCtx(ctx context.Context, name string) (TenantGroup, error) {
+	return findOne[TenantGroup](ctx, c, "tenant-group", fmt.Sprintf("name=%s", url.QueryEscape(name)))
+}
+
+// AddTenantGroup creates a new tenant group
+func (c *Client) AddTenantGroup(group TenantGroupEdit) (TenantGroup, error) {
+	return c.AddTenantGroupCtx(context.Background(), group)
+}
+
+// AddTenantGroupCtx is like AddTenantGroup but uses ctx for the request.
+func (c *Client) AddTenantGroupCtx(ctx context.Context, group TenantGroupEdit) (TenantGroup, error) {
+	if group.Slug == "" {
+		group.Slug = Slugify(group.Name)
+	}
+	return createObject[TenantGroup](ctx, c, "tenant-group", group)
+}
+
+// GetOrAddTenantGroup will retrieve the requested tenant group
+// by name and add it if it does not exist
+func (c *Client) GetOrAddTenantGroup(name string) (TenantGroup, error) {
+	return c.GetOrAddTenantGroupCtx(context.Background(), name)
+}
+
+// GetOrAddTenantGroupCtx is like GetOrAddTenantGroup but uses ctx for the requests.
+func (c *Client) GetOrAddTenantGroupCtx(ctx context.Context, name string) (TenantGroup, error) {
+	group, err := c.GetTenantGroupCtx(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddTenantGroupCtx(ctx, TenantGroupEdit{Name: name})
+	}
+	return group, err
+}
+
+// UpdateTenantGroup modifies the given fields of the tenant group
+func (c *Client) UpdateTenantGroup(id int, group TenantGroupEdit) (TenantGroup, error) {
+	return c.UpdateTenantGroupCtx(context.Background(), id, group)
+}
+
+// UpdateTenantGroupCtx is like UpdateTenantGroup but uses ctx for the request.
+func (c *Client) UpdateTenantGroupCtx(ctx context.Context, id int, group TenantGroupEdit) (TenantGroup, error) {
+	return updateObject[TenantGroup](ctx, c, "tenant-group", id, group)
+}
+
+// DeleteTenantGroup removes the tenant group from Netbox
+func (c *Client) DeleteTenantGroup(id int) error {
+	return c.DeleteTenantGroupCtx(context.Background(), id)
+}
+
+// DeleteTenantGroupCtx is like DeleteTenantGroup but uses ctx for the request.
+func (c *Client) DeleteTenantGroupCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "tenant-group", id)
+}
+
+// ListContacts returns all contacts that match the filter.  Filter
+// needs to be given as a valid api filter (eg. email=noc@example.com)
+func (c *Client) ListContacts(filter *string) ([]Contact, error) {
+	return c.ListContactsCtx(context.Background(), filter)
+}
+
+// ListContactsCtx is like ListContacts but uses ctx for the requests.
+func (c *Client) ListContactsCtx(ctx context.Context, filter *string) ([]Contact, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	return listAll[Contact](ctx, c, "contact", args)
+}
+
+// GetContact retrieves the contact with the given ID
+func (c *Client) GetContact(id int) (Contact, error) {
+	return c.GetContactCtx(context.Background(), id)
+}
+
+// GetContactCtx is like GetContact but uses ctx for the request.
+func (c *Client) GetContactCtx(ctx context.Context, id int) (Contact, error) {
+	return getObject[Contact](ctx, c, "contact", id)
+}
+
+// AddContact creates a new contact
+func (c *Client) AddContact(contact ContactEdit) (Contact, error) {
+	return c.AddContactCtx(context.Background(), contact)
+}
+
+// AddContactCtx is like AddContact but uses ctx for the request.
+func (c *Client) AddContactCtx(ctx context.Context, contact ContactEdit) (Contact, error) {
+	return createObject[Contact](ctx, c, "contact", contact)
+}
+
+// UpdateContact modifies the given fields of the contact
+func (c *Client) UpdateContact(id int, contact ContactEdit) (Contact, error) {
+	return c.UpdateContactCtx(context.Background(), id, contact)
+}
+
+// UpdateContactCtx is like UpdateContact but uses ctx for the request.
+func (c *Client) UpdateContactCtx(ctx context.Context, id int, contact ContactEdit) (Contact, error) {
+	return updateObject[Contact](ctx, c, "contact", id, contact)
+}
+
+// DeleteContact removes the contact from Netbox
+func (c *Client) DeleteContact(id int) error {
+	return c.DeleteContactCtx(context.Background(), id)
+}
+
+// DeleteContactCtx is like DeleteContact but uses ctx for the request.
+func (c *Client) DeleteContactCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "contact", id)
+}
+
+// GetOrAddContactRole retrieves the contact role by name, creating it
+// if it does not exist
+func (c *Client) GetOrAddContactRole(name string) (ContactRole, error) {
+	return c.GetOrAddContactRoleCtx(context.Background(), name)
+}
+
+// GetOrAddContactRoleCtx is like GetOrAddContactRole but uses ctx for the requests.
+func (c *Client) GetOrAddContactRoleCtx(ctx context.Context, name string) (ContactRole, error) {
+	role, err := findOne[ContactRole](ctx, c, "contact-role", fmt.Sprintf("slug=%s", Slugify(name)))
+	if errors.Is(err, ErrNotFound) {
+		data := map[string]interface{}{"name": name, "slug": Slugify(name)}
+		return createObject[ContactRole](ctx, c, "contact-role", data)
+	}
+	return role, err
+}
+
+// GetContactAssignments returns the contacts assigned to the given object
+func (c *Client) GetContactAssignments(model string, modelID int64) ([]ContactAssignment, error) {
+	return c.GetContactAssignmentsCtx(context.Background(), model, modelID)
+}
+
+// GetContactAssignmentsCtx is like GetContactAssignments but uses ctx for the requests.
+func (c *Client) GetContactAssignmentsCtx(ctx context.Context, model string, modelID int64) ([]ContactAssignment, error) {
+	// Netbox 4 renamed the content_type filter to object_type and ignores
+	// the one it does not know, so both are sent and the results checked.
+	objectType, err := getObjectType(model)
+	if err != nil {
+		return nil, err
+	}
+	all, err := listAll[ContactAssignment](ctx, c, "contact-assignment",
+		fmt.Sprintf("content_type=%s", objectType),
+		fmt.Sprintf("object_type=%s", objectType),
+		fmt.Sprintf("object_id=%d", modelID))
+	if err != nil {
+		return nil, err
+	}
+	var assignments []ContactAssignment
+	for _, a := range all {
+		if a.ContentType == objectType || a.ObjectType == objectType {
+			assignments = append(assignments, a)
+		}
+	}
+	return assignments, nil
+}
+
+// AssignContact assigns the contact in the given role to an object.
+// Priority may be empty or one of primary, secondary, tertiary or inactive.
+func (c *Client) AssignContact(model string, modelID int64, contactID int, roleID int, priority string) (ContactAssignment, error) {
+	return c.AssignContactCtx(context.Background(), model, modelID, contactID, roleID, priority)
+}
+
+// AssignContactCtx is like AssignContact but uses ctx for the request.
+func (c *Client) AssignContactCtx(ctx context.Context, model string, modelID int64, contactID int, roleID int, priority string) (ContactAssignment, error) {
+	objectType, err := getObjectType(model)
+	if err != nil {
+		return ContactAssignment{}, err
+	}
+	data := make(map[string]interface{})
+	data["content_type"] = objectType
+	data["object_type"] = objectType
+	data["object_id"] = modelID
+	data["contact"] = contactID
+	data["role"] = roleID
+	if priority != "" {
+		data["priority"] = priority
+	}
+	return createObject[ContactAssignment](ctx, c, "contact-assignment", data)
+}
+
+// DeleteContactAssignment removes the contact assignment from Netbox
+func (c *Client) DeleteContactAssignment(id int) error {
+	return c.DeleteContactAssignmentCtx(context.Background(), id)
+}
+
+// DeleteContactAssignmentCtx is like DeleteContactAssignment but uses ctx for the request.
+func (c *Client) DeleteContactAssignmentCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "contact-assignment", id)
+}
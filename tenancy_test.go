@@ -0,0 +1,47 @@
+package netbox
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetTenant(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/tenancy/tenants/5/" {
+			t.Errorf("request = %s %s, want GET /api/tenancy/tenants/5/", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 5, "name": "Acme", "slug": "acme", "group": {"id": 2, "name": "Customer"}}`))
+	}))
+	tenant, err := c.GetTenant(5)
+	if err != nil {
+		t.Fatalf("GetTenant() error = %v", err)
+	}
+	if tenant.ID != 5 || tenant.Name != "Acme" || tenant.Group == nil || tenant.Group.ID != 2 {
+		t.Errorf("GetTenant() = %+v", tenant)
+	}
+}
+
+func TestGetContactAssignments(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("object_type") != "dcim.device" || q.Get("content_type") != "dcim.device" || q.Get("object_id") != "5" {
+			t.Errorf("query = %s, want content_type and object_type dcim.device and object_id 5", r.URL.RawQuery)
+		}
+		// a server that ignores the type filter returns other objects with the same ID
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 3, "next": null, "previous": null, "results": [
+			{"id": 1, "object_type": "dcim.device", "object_id": 5},
+			{"id": 2, "object_type": "tenancy.tenant", "object_id": 5},
+			{"id": 3, "content_type": "dcim.device", "object_id": 5}]}`))
+	}))
+	assignments, err := c.GetContactAssignments("device", 5)
+	if err != nil {
+		t.Fatalf("GetContactAssignments() error = %v", err)
+	}
+	if len(assignments) != 2 || assignments[0].ID != 1 || assignments[1].ID != 3 {
+		t.Errorf("GetContactAssignments() = %+v, want assignments 1 and 3", assignments)
+	}
+}
@@ -1,6 +1,7 @@
 package netbox
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -30,40 +31,17 @@ type MonitoringSearchResults struct {
 }
 
 // getObjectType returns the full netbox object type for the given model.
-// For example, given the type of "device" will return "dcim.device"
-func getObjectType(aModel string) string {
-	var group string
-	switch aModel {
-	case "interface":
-		fallthrough
-	case "location":
-		fallthrough
-	case "device":
-		group = "dcim"
-	case "cluster-group":
-		aModel = "clustergroup"
-		group = "virtualization"
-	case "cluster-type":
-		aModel = "clustertype"
-		group = "virtualization"
-	case "vminterface":
-		fallthrough
-	case "cluster":
-		fallthrough
-	case "virtualmachine":
-		group = "virtualization"
-	case "ipaddress":
-		group = "ipam"
-	case "aggregate":
-		group = "ipam"
-	case "prefix":
-		group = "ipam"
-	case "ip-range":
-		group = "ipam"
-	default:
-		return "Invalid"
+// For example, given the type of "device" will return "dcim.device".
+// An *UnknownModelError is returned for models that are not registered.
+func getObjectType(aModel string) (string, error) {
+	m, err := LookupModel(aModel)
+	if err != nil {
+		return "", err
 	}
-	return fmt.Sprintf("%s.%s", group, aModel)
+	if m.ContentType == "" {
+		return "", fmt.Errorf("%w: model %s has no object type", ErrNotImplemented, aModel)
+	}
+	return m.ContentType, nil
 }
 
 func getJournalLevel(level JournalLevel) string {
@@ -92,12 +70,17 @@ type DeviceOrVM struct {
 	CustomFieldsMap map[string]interface{} `json:"custom_fields"`
 	Description     string                 `json:"description"`
 	DeviceRole      DisplayIDName          `json:"device_role"`
+	DeviceType      *DisplayIDName         `json:"device_type"`
 	Display         string                 `json:"display"`
+	Face            *LabelValue            `json:"face"`
 	ID              int                    `json:"id"`
 	LastUpdated     string                 `json:"last_updated"`
 	Latitude        *float64               `json:"latitude"`
 	Longitude       *float64               `json:"longitude"`
+	Location        *DisplayIDName         `json:"location"`
 	Name            string                 `json:"name"`
+	Platform        *DisplayIDName         `json:"platform"`
+	Position        *float64               `json:"position"`
 	PrimaryIP       PrimaryI               `json:"primary_ip"`
 	PrimaryIp4      PrimaryI               `json:"primary_ip4"`
 	Rack            struct {
@@ -106,14 +89,15 @@ type DeviceOrVM struct {
 		Name    string `json:"name"`
 		URL     string `json:"url"`
 	} `json:"rack"`
-	Role      DisplayIDName `json:"role"`
-	Serial    string        `json:"serial"`
-	Site      DisplayIDName `json:"site"`
-	Status    LabelValue    `json:"status"`
-	URL       string        `json:"url"`
-	Memory    int           `json:"memory,omitempty"`
-	Diskspace int           `json:"disk,omitempty"`
-	VCPUs     float32       `json:"vcpus,omitempty"`
+	Role      DisplayIDName  `json:"role"`
+	Serial    string         `json:"serial"`
+	Site      DisplayIDName  `json:"site"`
+	Status    LabelValue     `json:"status"`
+	Tenant    *DisplayIDName `json:"tenant"`
+	URL       string         `json:"url"`
+	Memory    int            `json:"memory,omitempty"`
+	Diskspace int            `json:"disk,omitempty"`
+	VCPUs     float32        `json:"vcpus,omitempty"`
 }
 type DisplayIDName struct {
 	Display string `json:"display"`
@@ -127,11 +111,11 @@ type LabelValue struct {
 	Value string `json:"value"`
 }
 type PrimaryI struct {
-	Address string      `json:"address"`
-	Display string      `json:"display"`
-	Family  interface{} `json:"family"`
-	ID      int         `json:"id"`
-	URL     string      `json:"url"`
+	Address string        `json:"address"`
+	Display string        `json:"display"`
+	Family  AddressFamily `json:"family"`
+	ID      int           `json:"id"`
+	URL     string        `json:"url"`
 }
 
 // DeviceVMSearchResults are returned for searches of
@@ -167,7 +151,7 @@ type Interface struct {
 	ID                          int                    `json:"id"`
 	L2vpnTermination            interface{}            `json:"l2vpn_termination"`
 	Label                       string                 `json:"label"`
-	Lag                         interface{}            `json:"lag"`
+	Lag                         *NestedInterface       `json:"lag"`
 	LastUpdated                 string                 `json:"last_updated"`
 	LinkPeers                   []struct {
 		Cable    int           `json:"cable"`
@@ -178,37 +162,38 @@ type Interface struct {
 		Occupied bool          `json:"_occupied"`
 		URL      string        `json:"url"`
 	} `json:"link_peers"`
-	LinkPeersType      *string       `json:"link_peers_type"`
-	MacAddress         *string       `json:"mac_address"`
-	MarkConnected      bool          `json:"mark_connected"`
-	MgmtOnly           bool          `json:"mgmt_only"`
-	Mode               interface{}   `json:"mode"`
-	Module             interface{}   `json:"module"`
-	Mtu                interface{}   `json:"mtu"`
-	Name               string        `json:"name"`
-	Occupied           bool          `json:"_occupied"`
-	Parent             interface{}   `json:"parent"`
-	PoeMode            interface{}   `json:"poe_mode"`
-	PoeType            interface{}   `json:"poe_type"`
-	RfChannel          interface{}   `json:"rf_channel"`
-	RfChannelFrequency interface{}   `json:"rf_channel_frequency"`
-	RfChannelWidth     interface{}   `json:"rf_channel_width"`
-	RfRole             interface{}   `json:"rf_role"`
-	Speed              *int          `json:"speed"`
-	TaggedVlans        []interface{} `json:"tagged_vlans"`
-	Tags               []interface{} `json:"tags"`
-	TxPower            interface{}   `json:"tx_power"`
+	LinkPeersType      *string          `json:"link_peers_type"`
+	MacAddress         *string          `json:"mac_address"`
+	MarkConnected      bool             `json:"mark_connected"`
+	MgmtOnly           bool             `json:"mgmt_only"`
+	Mode               *LabelValue      `json:"mode"`
+	Module             interface{}      `json:"module"`
+	Mtu                *int             `json:"mtu"`
+	Name               string           `json:"name"`
+	Occupied           bool             `json:"_occupied"`
+	Parent             *NestedInterface `json:"parent"`
+	PoeMode            interface{}      `json:"poe_mode"`
+	PoeType            interface{}      `json:"poe_type"`
+	RfChannel          interface{}      `json:"rf_channel"`
+	RfChannelFrequency interface{}      `json:"rf_channel_frequency"`
+	RfChannelWidth     interface{}      `json:"rf_channel_width"`
+	RfRole             interface{}      `json:"rf_role"`
+	Speed              *int             `json:"speed"`
+	TaggedVlans        []NestedVLAN     `json:"tagged_vlans"`
+	Tags               []Tag            `json:"tags"`
+	TxPower            interface{}      `json:"tx_power"`
 	Type               struct {
 		Label string `json:"label"`
 		Value string `json:"value"`
 	} `json:"type"`
-	URL          string        `json:"url"`
-	UntaggedVlan interface{}   `json:"untagged_vlan"`
-	Vdcs         []interface{} `json:"vdcs"`
-	Vrf          interface{}   `json:"vrf"`
-	WirelessLans []interface{} `json:"wireless_lans"`
-	WirelessLink interface{}   `json:"wireless_link"`
-	Wwn          interface{}   `json:"wwn"`
+	URL            string         `json:"url"`
+	UntaggedVlan   *NestedVLAN    `json:"untagged_vlan"`
+	Vdcs           []interface{}  `json:"vdcs"`
+	VirtualMachine *DisplayIDName `json:"virtual_machine"`
+	Vrf            *NestedVRF     `json:"vrf"`
+	WirelessLans   []interface{}  `json:"wireless_lans"`
+	WirelessLink   interface{}    `json:"wireless_link"`
+	Wwn            interface{}    `json:"wwn"`
 }
 
 func (i *Interface) GetSpeed() int {
@@ -233,6 +218,15 @@ func (i *Interface) GetMacAddress() string {
 	return mac
 }
 
+// NestedInterface is the brief form of an interface found on the parent
+// and LAG of another interface
+type NestedInterface struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Name    string `json:"name"`
+}
+
 type InterfacesResponse struct {
 	Count    int         `json:"count"`
 	Next     *string     `json:"next"`
@@ -242,18 +236,69 @@ type InterfacesResponse struct {
 
 // InterfaceEdit is used to add/update an interface
 type InterfaceEdit struct {
-	Description string      `json:"description,omitempty"`
-	Device      *int        `json:"device,omitempty"`
-	VM          *int        `json:"virtual_machine,omitempty"`
-	Display     *string     `json:"display,omitempty"`
-	Duplex      *string     `json:"duplex,omitempty"`
-	Label       *string     `json:"label,omitempty"`
-	Lag         interface{} `json:"lag,omitempty"`
-	MacAddress  *string     `json:"mac_address,omitempty"`
-	Name        *string     `json:"name,omitempty"`
-	Speed       *int        `json:"speed,omitempty"`
-	Type        *string     `json:"type,omitempty"`
-	Parent      *int        `json:"parent,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Device      *int    `json:"device,omitempty"`
+	VM          *int    `json:"virtual_machine,omitempty"`
+	Display     *string `json:"display,omitempty"`
+	Duplex      *string `json:"duplex,omitempty"`
+	Label       *string `json:"label,omitempty"`
+	Lag         *int    `json:"lag,omitempty"`
+	MacAddress  *string `json:"mac_address,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Speed       *int    `json:"speed,omitempty"`
+	Type        *string `json:"type,omitempty"`
+	Parent      *int    `json:"parent,omitempty"`
+	// Mode is the 802.1Q mode: "access", "tagged" or "tagged-all"
+	Mode         *string                `json:"mode,omitempty"`
+	UntaggedVlan *int                   `json:"untagged_vlan,omitempty"`
+	TaggedVlans  []int                  `json:"tagged_vlans,omitempty"`
+	Enabled      *bool                  `json:"enabled,omitempty"`
+	Mtu          *int                   `json:"mtu,omitempty"`
+	MgmtOnly     *bool                  `json:"mgmt_only,omitempty"`
+	Vrf          *int                   `json:"vrf,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	// ParentName and LagName refer to the parent and LAG interface of the
+	// same device by name.  They are resolved when Parent or Lag is not
+	// set and are not sent to Netbox.
+	ParentName string `json:"-"`
+	LagName    string `json:"-"`
+
+	// clearDescription is set by SetDescription("") to send the empty
+	// description.
+	clearDescription bool
+}
+
+// MarshalJSON sends an empty, non-nil TaggedVlans as an empty list so
+// the tagged VLANs are removed, and a description cleared with
+// SetDescription as "", where omitempty would leave them.
+func (i InterfaceEdit) MarshalJSON() ([]byte, error) {
+	type edit InterfaceEdit
+	clearVlans := i.TaggedVlans != nil && len(i.TaggedVlans) == 0
+	clearDescription := i.clearDescription && i.Description == ""
+	data, err := json.Marshal(edit(i))
+	if err != nil || (!clearVlans && !clearDescription) {
+		return data, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if clearVlans {
+		fields["tagged_vlans"] = json.RawMessage(`[]`)
+	}
+	if clearDescription {
+		fields["description"] = json.RawMessage(`""`)
+	}
+	return json.Marshal(fields)
+}
+
+// SetDescription sets the description to update.  An empty description
+// removes it.  Returns true if the value is changed
+func (i *InterfaceEdit) SetDescription(description string) bool {
+	i.Description = description
+	i.clearDescription = description == ""
+	return true
 }
 
 // SetSpeed sets the speed to update.  Returns true
@@ -303,3 +348,100 @@ func (i *InterfaceEdit) SetParent(parent int) bool {
 	}
 	return false
 }
+
+// SetMode sets the 802.1Q mode to update.  Returns true
+// if the value is changed
+func (i *InterfaceEdit) SetMode(mode string) bool {
+	if mode == "" {
+		return false
+	}
+	i.Mode = &mode
+	return true
+}
+
+// SetUntaggedVlan sets the untagged VLAN by ID.  Returns true
+// if the value is changed
+func (i *InterfaceEdit) SetUntaggedVlan(vlan int) bool {
+	if vlan != 0 {
+		i.UntaggedVlan = &vlan
+		return true
+	}
+	return false
+}
+
+// SetTaggedVlans sets the tagged VLANs by ID.  An empty, non-nil
+// slice removes all tagged VLANs.  Returns true if the value is changed
+func (i *InterfaceEdit) SetTaggedVlans(vlans []int) bool {
+	if vlans == nil {
+		return false
+	}
+	i.TaggedVlans = vlans
+	return true
+}
+
+// SetLag sets the LAG interface by ID.  Returns true
+// if the value is changed
+func (i *InterfaceEdit) SetLag(lag int) bool {
+	if lag != 0 {
+		i.Lag = &lag
+		return true
+	}
+	return false
+}
+
+// SetEnabled sets whether the interface is enabled.  Returns true
+// if the value is changed
+func (i *InterfaceEdit) SetEnabled(enabled bool) bool {
+	i.Enabled = &enabled
+	return true
+}
+
+// SetMtu sets the MTU to update.  Returns true
+// if the value is changed
+func (i *InterfaceEdit) SetMtu(mtu int) bool {
+	if mtu == 0 {
+		return false
+	}
+	i.Mtu = &mtu
+	return true
+}
+
+// SetMgmtOnly sets whether the interface is for management only.  Returns
+// true if the value is changed
+func (i *InterfaceEdit) SetMgmtOnly(mgmtOnly bool) bool {
+	i.MgmtOnly = &mgmtOnly
+	return true
+}
+
+// SetVrf sets the VRF by ID.  Returns true
+// if the value is changed
+func (i *InterfaceEdit) SetVrf(vrf int) bool {
+	if vrf != 0 {
+		i.Vrf = &vrf
+		return true
+	}
+	return false
+}
+
+// SetTags replaces the tags of the interface.  Returns true
+// if the value is changed
+func (i *InterfaceEdit) SetTags(tags []Tag) bool {
+	if tags == nil {
+		return false
+	}
+	i.Tags = tags
+	return true
+}
+
+// SetCustomField sets a single custom field.  Returns true
+// if the value is changed
+func (i *InterfaceEdit) SetCustomField(name string, value interface{}) bool {
+	if name == "" {
+		return false
+	}
+	if i.CustomFields == nil {
+		i.CustomFields = make(map[string]interface{})
+	}
+	i.CustomFields[name] = value
+	return true
+}
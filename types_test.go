@@ -1,15 +1,20 @@
 package netbox
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
 
 func Test_getObjectType(t *testing.T) {
 	type args struct {
 		aModel string
 	}
 	tests := []struct {
-		name string
-		args args
-		want string
+		name    string
+		args    args
+		want    string
+		wantErr error
 	}{
 		{
 			name: "Test passing a string",
@@ -17,16 +22,84 @@ func Test_getObjectType(t *testing.T) {
 			want: "dcim.device",
 		},
 		{
-			name: "Test an invalid",
-			args: args{aModel: "dummy"},
-			want: "Invalid",
+			name:    "Test an invalid",
+			args:    args{aModel: "dummy"},
+			wantErr: ErrUnknownModel,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getObjectType(tt.args.aModel); got != tt.want {
-				t.Errorf("getModel() = %v, want %v", got, tt.want)
+			got, err := getObjectType(tt.args.aModel)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("getObjectType() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getObjectType() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestLookupModel(t *testing.T) {
+	for _, name := range []string{"ipaddress", "ip-address"} {
+		m, err := LookupModel(name)
+		if err != nil || m.Path != "/ipam/ip-addresses" || m.ContentType != "ipam.ipaddress" {
+			t.Errorf("LookupModel(%q) = %+v, %v", name, m, err)
+		}
+	}
+	if _, err := LookupModel("dummy"); !errors.Is(err, ErrUnknownModel) {
+		t.Errorf("LookupModel(dummy) error = %v, want %v", err, ErrUnknownModel)
+	}
+
+	plugin := Model{Name: "bgp-session", Path: "/plugins/bgp/session", ContentType: "netbox_bgp.bgpsession", Operations: OpReadOnly}
+	if err := RegisterModel(plugin); err != nil {
+		t.Fatalf("RegisterModel() error = %v", err)
+	}
+	if got := GetPathForModel("bgp-session"); got != plugin.Path {
+		t.Errorf("GetPathForModel(bgp-session) = %q, want %q", got, plugin.Path)
+	}
+	if m, _ := LookupModel("bgp-session"); m.Supports(OpCreate) || !m.Supports(OpList|OpGet) {
+		t.Errorf("bgp-session operations = %b", m.Operations)
+	}
+	if got, err := getObjectType("location"); err != nil || got != "dcim.location" {
+		t.Errorf("getObjectType(location) = %q, %v", got, err)
+	}
+	if got := GetPathForModel("location"); got != "/dcim/locations" {
+		t.Errorf("GetPathForModel(location) = %q", got)
+	}
+}
+
+func TestInterfaceEditTaggedVlansJSON(t *testing.T) {
+	tests := []struct {
+		vlans []int
+		want  string
+	}{
+		{nil, `{}`},
+		{[]int{}, `{"tagged_vlans":[]}`},
+		{[]int{10, 20}, `{"tagged_vlans":[10,20]}`},
+	}
+	for _, tt := range tests {
+		edit := InterfaceEdit{}
+		edit.SetTaggedVlans(tt.vlans)
+		got, err := json.Marshal(edit)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("json.Marshal(%v) = %s, %v, want %s", tt.vlans, got, err, tt.want)
+		}
+	}
+}
+
+func TestInterfaceEditDescriptionJSON(t *testing.T) {
+	edit := InterfaceEdit{}
+	if got, _ := json.Marshal(edit); string(got) != `{}` {
+		t.Errorf("json.Marshal() without a description = %s, want {}", got)
+	}
+	edit.SetDescription("")
+	edit.SetTaggedVlans([]int{})
+	if got, err := json.Marshal(edit); err != nil || string(got) != `{"description":"","tagged_vlans":[]}` {
+		t.Errorf("json.Marshal() clearing the description = %s, %v", got, err)
+	}
+	edit.SetDescription("uplink")
+	if got, _ := json.Marshal(edit); string(got) != `{"description":"uplink","tagged_vlans":[]}` {
+		t.Errorf("json.Marshal() with a description = %s", got)
+	}
+}
@@ -1,45 +1,31 @@
 package netbox
 
-import "strings"
+import (
+	"net/netip"
+	"strings"
+)
 
 // IPfromCIDR takes an IP address in CIDR notation
-// and returns just the IP without the mask.
+// and returns just the IP without the mask.  Valid addresses are
+// returned in canonical form, so IPv6 addresses compare equal.
 func IPfromCIDR(cidr string) string {
+	if prefix, err := netip.ParsePrefix(cidr); err == nil {
+		return prefix.Addr().String()
+	}
+	if addr, err := netip.ParseAddr(cidr); err == nil {
+		return addr.String()
+	}
 	return strings.Split(cidr, "/")[0]
 }
 
+// GetPathForModel returns the API path of the registered model, or an
+// empty string if the model is unknown.
+//
+// Deprecated: use LookupModel, which reports unknown models.
 func GetPathForModel(model string) string {
-	path := ""
-
-	switch model {
-	case "interface":
-		path = "/dcim/interfaces"
-	case "device":
-		path = "/dcim/devices"
-	case "ipaddress":
-		fallthrough
-	case "ip-address":
-		path = "/ipam/ip-addresses"
-	case "virtualmachine":
-		path = "/virtualization/virtual-machines"
-	case "cluster":
-		path = "/virtualization/clusters"
-	case "cluster-group":
-		path = "/virtualization/cluster-groups"
-	case "cluster-type":
-		path = "/virtualization/cluster-types"
-	case "vminterface":
-		path = "/virtualization/interfaces"
-	case "customfield":
-		fallthrough
-	case "custom-field":
-		path = "/extras/custom-fields"
-	case "aggregate":
-		path = "/ipam/aggregates"
-	case "prefix":
-		path = "/ipam/prefixes"
-	case "ip-range":
-		path = "/ipam/ip-ranges"
+	m, err := LookupModel(model)
+	if err != nil {
+		return ""
 	}
-	return path
+	return m.Path
 }
@@ -1,6 +1,7 @@
 package netbox
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -92,34 +93,33 @@ type NewVM struct {
 // GetClusterGroups returns all clusters that match the filter.  Filter
 // needs to be given as a valid api filter (eg. tag=apc)
 func (c *Client) GetClusterGroups(filter *string) ([]ClusterGroup, error) {
-	var groups []ClusterGroup
-	results := &ClusterGroupResponse{}
+	return c.GetClusterGroupsCtx(context.Background(), filter)
+}
+
+// GetClusterGroupsCtx is like GetClusterGroups but uses ctx for the requests.
+func (c *Client) GetClusterGroupsCtx(ctx context.Context, filter *string) ([]ClusterGroup, error) {
 	var args string
 	if filter != nil {
 		args = *filter
 	}
-	err := c.Search("cluster-group", results, args)
+	groups, err := listAll[ClusterGroup](ctx, c, "cluster-group", args)
 	if err != nil {
 		c.log.Error("error finding cluster groups", "filter", filter, "error", err)
 		return groups, err
 	}
-	groups = append(groups, results.Results...)
-	for results.Next != nil {
-		_, err := c.GetByURL(fmt.Sprint(results.Next), results)
-		if err != nil {
-			c.log.Error("error getting cluster groups", "filter", filter, "error", err)
-			return groups, err
-		}
-		groups = append(groups, results.Results...)
-	}
 	return groups, nil
 }
 
 // GetClusterGroup looks up the cluster by name
 func (c *Client) GetClusterGroup(name string) (ClusterGroup, error) {
+	return c.GetClusterGroupCtx(context.Background(), name)
+}
+
+// GetClusterGroupCtx is like GetClusterGroup but uses ctx for the requests.
+func (c *Client) GetClusterGroupCtx(ctx context.Context, name string) (ClusterGroup, error) {
 	var group ClusterGroup
 	filter := fmt.Sprintf("name=%s", url.QueryEscape(name))
-	results, err := c.GetClusterGroups(&filter)
+	results, err := c.GetClusterGroupsCtx(ctx, &filter)
 	if err != nil {
 		return group, err
 	}
@@ -129,25 +129,33 @@ func (c *Client) GetClusterGroup(name string) (ClusterGroup, error) {
 	case 1:
 		return results[0], nil
 	}
-	return group, errors.New("too many results returned")
+	return group, ErrMultipleResults
 }
 
 // AddClusterGroup creates the request group in netbox
 func (c *Client) AddClusterGroup(name string) (ClusterGroup, error) {
+	return c.AddClusterGroupCtx(context.Background(), name)
+}
+
+// AddClusterGroupCtx is like AddClusterGroup but uses ctx for the request.
+func (c *Client) AddClusterGroupCtx(ctx context.Context, name string) (ClusterGroup, error) {
 	group := ClusterGroup{}
 	data := make(map[string]interface{})
 	data["name"] = name
 	data["slug"] = Slugify(name)
-	r := c.buildRequest().SetResult(&group)
+	r := c.buildRequest(ctx).SetResult(&group)
 	r.SetBody(data)
-	path := GetPathForModel("cluster-group") + "/"
-	resp, err := r.Post(c.buildURL(path))
+	path, err := c.modelURL(OpCreate, "cluster-group")
+	if err != nil {
+		return group, err
+	}
+	resp, err := r.Post(path)
 	if err != nil {
 		return group, err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error("error adding cluster group", "cluster group", name, "error", err)
-		return group, errors.New("error adding cluster group")
+		return group, err
 	}
 	return group, nil
 }
@@ -155,12 +163,17 @@ func (c *Client) AddClusterGroup(name string) (ClusterGroup, error) {
 // GetOrAddClusterGroup will retrieve the requested cluster group
 // by name and add it if it does not exist
 func (c *Client) GetOrAddClusterGroup(name string) (ClusterGroup, error) {
-	cluster, err := c.GetClusterGroup(name)
+	return c.GetOrAddClusterGroupCtx(context.Background(), name)
+}
+
+// GetOrAddClusterGroupCtx is like GetOrAddClusterGroup but uses ctx for the requests.
+func (c *Client) GetOrAddClusterGroupCtx(ctx context.Context, name string) (ClusterGroup, error) {
+	cluster, err := c.GetClusterGroupCtx(ctx, name)
 	if err == nil {
 		return cluster, err
 	}
 	if errors.Is(err, ErrNotFound) {
-		return c.AddClusterGroup(name)
+		return c.AddClusterGroupCtx(ctx, name)
 	}
 	return cluster, err
 }
@@ -168,40 +181,38 @@ func (c *Client) GetOrAddClusterGroup(name string) (ClusterGroup, error) {
 // GetClusters searches for all clusters with the given filter.  The
 // filter needs to be specified as an API filter, eg. tag=apc
 func (c *Client) GetClusters(filter *string) ([]Cluster, error) {
-	var clusters []Cluster
-	var args string
-	results := &ClusterResponse{}
+	return c.GetClustersCtx(context.Background(), filter)
+}
 
+// GetClustersCtx is like GetClusters but uses ctx for the requests.
+func (c *Client) GetClustersCtx(ctx context.Context, filter *string) ([]Cluster, error) {
+	var args string
 	if filter != nil {
 		args = *filter
 	}
-	err := c.Search("cluster", results, args)
+	clusters, err := listAll[Cluster](ctx, c, "cluster", args)
 	if err != nil {
 		c.log.Error("error finding clusters", "filter", filter, "error", err)
 		return clusters, err
 	}
-	clusters = append(clusters, results.Results...)
-	for results.Next != nil {
-		_, err = c.GetByURL(fmt.Sprint(results.Next), results)
-		if err != nil {
-			c.log.Error("error finding next clusters", "filter", filter, "error", err)
-			return clusters, err
-		}
-		clusters = append(clusters, results.Results...)
-	}
 	return clusters, nil
 }
 
 // GetCluster looks up the cluster with the given name in the given group
 func (c *Client) GetCluster(group string, name string) (Cluster, error) {
+	return c.GetClusterCtx(context.Background(), group, name)
+}
+
+// GetClusterCtx is like GetCluster but uses ctx for the requests.
+func (c *Client) GetClusterCtx(ctx context.Context, group string, name string) (Cluster, error) {
 	var cluster Cluster
 	results := &ClusterResponse{}
-	cGroup, err := c.GetClusterGroup(group)
+	cGroup, err := c.GetClusterGroupCtx(ctx, group)
 	if err != nil {
 		c.log.Error("Cannot determine cluster group id", "group", group, "error", err)
 		return cluster, err
 	}
-	err = c.Search("cluster", results, fmt.Sprintf("group_id=%d&name=%s", cGroup.ID, url.QueryEscape(name)))
+	err = c.SearchCtx(ctx, "cluster", results, fmt.Sprintf("group_id=%d&name=%s", cGroup.ID, url.QueryEscape(name)))
 	if err != nil {
 		c.log.Error("error finding cluster", "cluster", name, "error", err)
 		return cluster, err
@@ -212,18 +223,23 @@ func (c *Client) GetCluster(group string, name string) (Cluster, error) {
 	case 1:
 		return results.Results[0], nil
 	}
-	return cluster, errors.New("too many results returned")
+	return cluster, ErrMultipleResults
 }
 
 // AddCluster creates a new cluster in the given group
 func (c *Client) AddCluster(group string, name string, clusterType string) (Cluster, error) {
+	return c.AddClusterCtx(context.Background(), group, name, clusterType)
+}
+
+// AddClusterCtx is like AddCluster but uses ctx for the requests.
+func (c *Client) AddClusterCtx(ctx context.Context, group string, name string, clusterType string) (Cluster, error) {
 	var cluster Cluster
-	cGroup, err := c.GetClusterGroup(group)
+	cGroup, err := c.GetClusterGroupCtx(ctx, group)
 	if err != nil {
 		c.log.Error("Cannot determine cluster group id", "group", group, "error", err)
 		return cluster, err
 	}
-	cType, err := c.GetClusterType(clusterType)
+	cType, err := c.GetClusterTypeCtx(ctx, clusterType)
 	if err != nil {
 		return cluster, err
 	}
@@ -231,17 +247,20 @@ func (c *Client) AddCluster(group string, name string, clusterType string) (Clus
 	data["name"] = name
 	data["group"] = cGroup.ID
 	data["type"] = cType.ID
-	r := c.buildRequest().SetResult(&cluster)
+	r := c.buildRequest(ctx).SetResult(&cluster)
 	r.SetBody(data)
-	path := GetPathForModel("cluster") + "/"
-	resp, err := r.Post(c.buildURL(path))
+	path, err := c.modelURL(OpCreate, "cluster")
+	if err != nil {
+		return cluster, err
+	}
+	resp, err := r.Post(path)
 	if err != nil {
 		c.log.Error("error communicating with netbox adding the cluster", "cluster", name, "error", err)
 		return cluster, err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error("error adding cluster", "cluster", name, "error", err)
-		return cluster, errors.New("error adding cluster")
+		return cluster, err
 	}
 	return cluster, nil
 }
@@ -249,12 +268,17 @@ func (c *Client) AddCluster(group string, name string, clusterType string) (Clus
 // GetOrAddCluster will retrieve the cluster if found and add it if it does
 // not exist.
 func (c *Client) GetOrAddCluster(group string, name string, clusterType string) (Cluster, error) {
-	cluster, err := c.GetCluster(group, name)
+	return c.GetOrAddClusterCtx(context.Background(), group, name, clusterType)
+}
+
+// GetOrAddClusterCtx is like GetOrAddCluster but uses ctx for the requests.
+func (c *Client) GetOrAddClusterCtx(ctx context.Context, group string, name string, clusterType string) (Cluster, error) {
+	cluster, err := c.GetClusterCtx(ctx, group, name)
 	if err == nil {
 		return cluster, err
 	}
 	if errors.Is(err, ErrNotFound) {
-		return c.AddCluster(group, name, clusterType)
+		return c.AddClusterCtx(ctx, group, name, clusterType)
 	}
 	return cluster, err
 }
@@ -263,15 +287,36 @@ func (c *Client) GetOrAddCluster(group string, name string, clusterType string)
 // endpoint for the given args.  Args should be specified as
 // key=value (eg. has_primary_ip=true)
 func (c *Client) SearchVMs(args ...string) ([]DeviceOrVM, error) {
-	return c.performDevVMsearch("virtualmachine", args...)
+	return c.SearchVMsCtx(context.Background(), args...)
+}
+
+// SearchVMsCtx is like SearchVMs but uses ctx for the requests.
+func (c *Client) SearchVMsCtx(ctx context.Context, args ...string) ([]DeviceOrVM, error) {
+	return c.performDevVMsearch(ctx, "virtualmachine", args...)
+}
+
+// WalkVMs calls fn for each virtual machine matching args, one page at a
+// time, until fn returns false.  Args are given as for SearchVMs.
+func (c *Client) WalkVMs(fn func(DeviceOrVM) bool, args ...string) error {
+	return c.WalkVMsCtx(context.Background(), nil, fn, args...)
+}
+
+// WalkVMsCtx is like WalkVMs but uses ctx for the requests and opts for paging.
+func (c *Client) WalkVMsCtx(ctx context.Context, opts *ListOptions, fn func(DeviceOrVM) bool, args ...string) error {
+	return c.walkDevVMs(ctx, "virtualmachine", opts, fn, args...)
 }
 
 // GetClusterType looks up the type by name
 func (c *Client) GetClusterType(name string) (ClusterType, error) {
+	return c.GetClusterTypeCtx(context.Background(), name)
+}
+
+// GetClusterTypeCtx is like GetClusterType but uses ctx for the request.
+func (c *Client) GetClusterTypeCtx(ctx context.Context, name string) (ClusterType, error) {
 	var cType ClusterType
 	results := &ClusterTypesResponse{}
 	args := []string{fmt.Sprintf("slug=%s", Slugify(name))}
-	err := c.Search("cluster-type", results, args...)
+	err := c.SearchCtx(ctx, "cluster-type", results, args...)
 	if err != nil {
 		c.log.Error("error finding cluster type", "type", name, "error", err)
 		return cType, err
@@ -282,27 +327,35 @@ func (c *Client) GetClusterType(name string) (ClusterType, error) {
 	case 1:
 		return results.Results[0], nil
 	}
-	return cType, errors.New("too many results returned")
+	return cType, ErrMultipleResults
 }
 
 // AddClusterType will create a new type
 func (c *Client) AddClusterType(name string) (ClusterType, error) {
+	return c.AddClusterTypeCtx(context.Background(), name)
+}
+
+// AddClusterTypeCtx is like AddClusterType but uses ctx for the request.
+func (c *Client) AddClusterTypeCtx(ctx context.Context, name string) (ClusterType, error) {
 	clusterType := ClusterType{}
 	data := make(map[string]interface{})
 	data["name"] = name
 	data["slug"] = Slugify(name)
 
-	r := c.buildRequest().SetResult(&clusterType)
+	r := c.buildRequest(ctx).SetResult(&clusterType)
 	r.SetBody(data)
-	path := GetPathForModel("cluster-type") + "/"
-	resp, err := r.Post(c.buildURL(path))
+	path, err := c.modelURL(OpCreate, "cluster-type")
+	if err != nil {
+		return clusterType, err
+	}
+	resp, err := r.Post(path)
 	if err != nil {
 		c.log.Error("could not create cluster type", "name", name, "error", err)
 		return clusterType, err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error("invalid response from Netbox", "status", resp.StatusCode(), "body", resp.Body())
-		return clusterType, fmt.Errorf("%s: %s", resp.Error(), resp.Body())
+		return clusterType, err
 	}
 	return clusterType, nil
 }
@@ -310,18 +363,26 @@ func (c *Client) AddClusterType(name string) (ClusterType, error) {
 // AddVM creates the requested VM in netbox
 // If clusterID == 0 the VM will not be added to a cluster
 func (c *Client) AddVM(newvm NewVM) (DeviceOrVM, error) {
+	return c.AddVMCtx(context.Background(), newvm)
+}
+
+// AddVMCtx is like AddVM but uses ctx for the request.
+func (c *Client) AddVMCtx(ctx context.Context, newvm NewVM) (DeviceOrVM, error) {
 	vm := DeviceOrVM{}
 
-	r := c.buildRequest().SetResult(&vm)
+	r := c.buildRequest(ctx).SetResult(&vm)
 	r.SetBody(newvm)
-	path := GetPathForModel("virtualmachine") + "/"
-	resp, err := r.Post(c.buildURL(path))
+	path, err := c.modelURL(OpCreate, "virtualmachine")
+	if err != nil {
+		return vm, err
+	}
+	resp, err := r.Post(path)
 	if err != nil {
 		return vm, err
 	}
-	if resp.IsError() {
+	if err = checkStatus(resp); err != nil {
 		c.log.Error("error adding VM", "name", newvm.Name, "url", r.URL, "status", resp.StatusCode(), "error", resp.Body())
-		return vm, errors.New("failed to add VM")
+		return vm, err
 	}
 	setDeviceCustomFields(&vm)
 	return vm, nil
@@ -0,0 +1,263 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+type VLAN struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Vid          int                    `json:"vid"`
+	Name         string                 `json:"name"`
+	Site         *DisplayIDName         `json:"site"`
+	Group        *DisplayIDName         `json:"group"`
+	Tenant       *DisplayIDName         `json:"tenant"`
+	Status       LabelValue             `json:"status"`
+	Role         *DisplayIDName         `json:"role"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	Created      string                 `json:"created"`
+	LastUpdated  string                 `json:"last_updated"`
+}
+
+// NestedVLAN is the brief form of a VLAN found on interfaces
+type NestedVLAN struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Vid     int    `json:"vid"`
+	Name    string `json:"name"`
+}
+
+// VLANEdit is used to add/update a VLAN
+type VLANEdit struct {
+	Vid          int                    `json:"vid,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Site         *int                   `json:"site,omitempty"`
+	Group        *int                   `json:"group,omitempty"`
+	Tenant       *int                   `json:"tenant,omitempty"`
+	Status       *string                `json:"status,omitempty"`
+	Role         *int                   `json:"role,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type VLANGroup struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Name         string                 `json:"name"`
+	Slug         string                 `json:"slug"`
+	ScopeType    *string                `json:"scope_type"`
+	ScopeID      *int                   `json:"scope_id"`
+	MinVid       int                    `json:"min_vid"`
+	MaxVid       int                    `json:"max_vid"`
+	Description  string                 `json:"description"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	VlanCount    int                    `json:"vlan_count"`
+}
+
+// VLANGroupEdit is used to add/update a VLAN group.  ScopeType is the
+// content type of the scope, eg. "dcim.site".
+type VLANGroupEdit struct {
+	Name         string                 `json:"name,omitempty"`
+	Slug         string                 `json:"slug,omitempty"`
+	ScopeType    *string                `json:"scope_type,omitempty"`
+	ScopeID      *int                   `json:"scope_id,omitempty"`
+	MinVid       *int                   `json:"min_vid,omitempty"`
+	MaxVid       *int                   `json:"max_vid,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// ListVLANs returns all VLANs that match the filter.  Filter
+// needs to be given as a valid api filter (eg. site_id=1)
+func (c *Client) ListVLANs(filter *string) ([]VLAN, error) {
+	return c.ListVLANsCtx(context.Background(), filter)
+}
+
+// ListVLANsCtx is like ListVLANs but uses ctx for the requests.
+func (c *Client) ListVLANsCtx(ctx context.Context, filter *string) ([]VLAN, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	vlans, err := listAll[VLAN](ctx, c, "vlan", args)
+	if err != nil {
+		c.log.Error("error finding vlans", "filter", filter, "error", err)
+	}
+	return vlans, err
+}
+
+// GetVLAN retrieves the VLAN with the given ID
+func (c *Client) GetVLAN(id int) (VLAN, error) {
+	return c.GetVLANCtx(context.Background(), id)
+}
+
+// GetVLANCtx is like GetVLAN but uses ctx for the request.
+func (c *Client) GetVLANCtx(ctx context.Context, id int) (VLAN, error) {
+	return getObject[VLAN](ctx, c, "vlan", id)
+}
+
+// GetVLANInGroup looks up the VLAN by VID within the VLAN group
+func (c *Client) GetVLANInGroup(groupID int, vid int) (VLAN, error) {
+	return c.GetVLANInGroupCtx(context.Background(), groupID, vid)
+}
+
+// GetVLANInGroupCtx is like GetVLANInGroup but uses ctx for the request.
+func (c *Client) GetVLANInGroupCtx(ctx context.Context, groupID int, vid int) (VLAN, error) {
+	return findOne[VLAN](ctx, c, "vlan", fmt.Sprintf("group_id=%d", groupID), fmt.Sprintf("vid=%d", vid))
+}
+
+// GetVLANInSite looks up the VLAN by VID among the VLANs assigned
+// directly to the site
+func (c *Client) GetVLANInSite(siteID int, vid int) (VLAN, error) {
+	return c.GetVLANInSiteCtx(context.Background(), siteID, vid)
+}
+
+// GetVLANInSiteCtx is like GetVLANInSite but uses ctx for the request.
+func (c *Client) GetVLANInSiteCtx(ctx context.Context, siteID int, vid int) (VLAN, error) {
+	return findOne[VLAN](ctx, c, "vlan", fmt.Sprintf("site_id=%d", siteID), "group_id=null", fmt.Sprintf("vid=%d", vid))
+}
+
+// AddVLAN creates a new VLAN
+func (c *Client) AddVLAN(vlan VLANEdit) (VLAN, error) {
+	return c.AddVLANCtx(context.Background(), vlan)
+}
+
+// AddVLANCtx is like AddVLAN but uses ctx for the request.
+func (c *Client) AddVLANCtx(ctx context.Context, vlan VLANEdit) (VLAN, error) {
+	return createObject[VLAN](ctx, c, "vlan", vlan)
+}
+
+// GetOrAddVLAN will retrieve the VLAN with the VID in vlan.Group, or in
+// vlan.Site when no group is given, and add it if it does not exist.
+// Without a group or site only global VLANs are searched.
+func (c *Client) GetOrAddVLAN(vlan VLANEdit) (VLAN, error) {
+	return c.GetOrAddVLANCtx(context.Background(), vlan)
+}
+
+// GetOrAddVLANCtx is like GetOrAddVLAN but uses ctx for the requests.
+func (c *Client) GetOrAddVLANCtx(ctx context.Context, vlan VLANEdit) (VLAN, error) {
+	var existing VLAN
+	var err error
+	switch {
+	case vlan.Group != nil:
+		existing, err = c.GetVLANInGroupCtx(ctx, *vlan.Group, vlan.Vid)
+	case vlan.Site != nil:
+		existing, err = c.GetVLANInSiteCtx(ctx, *vlan.Site, vlan.Vid)
+	default:
+		existing, err = findOne[VLAN](ctx, c, "vlan", "site_id=null", "group_id=null", fmt.Sprintf("vid=%d", vlan.Vid))
+	}
+	if errors.Is(err, ErrNotFound) {
+		return c.AddVLANCtx(ctx, vlan)
+	}
+	return existing, err
+}
+
+// UpdateVLAN modifies the given fields of the VLAN
+func (c *Client) UpdateVLAN(id int, vlan VLANEdit) (VLAN, error) {
+	return c.UpdateVLANCtx(context.Background(), id, vlan)
+}
+
+// UpdateVLANCtx is like UpdateVLAN but uses ctx for the request.
+func (c *Client) UpdateVLANCtx(ctx context.Context, id int, vlan VLANEdit) (VLAN, error) {
+	return updateObject[VLAN](ctx, c, "vlan", id, vlan)
+}
+
+// DeleteVLAN removes the VLAN from Netbox
+func (c *Client) DeleteVLAN(id int) error {
+	return c.DeleteVLANCtx(context.Background(), id)
+}
+
+// DeleteVLANCtx is like DeleteVLAN but uses ctx for the request.
+func (c *Client) DeleteVLANCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "vlan", id)
+}
+
+// ListVLANGroups returns all VLAN groups that match the filter.  Filter
+// needs to be given as a valid api filter (eg. site_id=1)
+func (c *Client) ListVLANGroups(filter *string) ([]VLANGroup, error) {
+	return c.ListVLANGroupsCtx(context.Background(), filter)
+}
+
+// ListVLANGroupsCtx is like ListVLANGroups but uses ctx for the requests.
+func (c *Client) ListVLANGroupsCtx(ctx context.Context, filter *string) ([]VLANGroup, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	groups, err := listAll[VLANGroup](ctx, c, "vlan-group", args)
+	if err != nil {
+		c.log.Error("error finding vlan groups", "filter", filter, "error", err)
+	}
+	return groups, err
+}
+
+// GetVLANGroup looks up the VLAN group by name
+func (c *Client) GetVLANGroup(name string) (VLANGroup, error) {
+	return c.GetVLANGroupCtx(context.Background(), name)
+}
+
+// GetVLANGroupCtx is like GetVLANGroup but uses ctx for the request.
+func (c *Client) GetVLANGroupCtx(ctx context.Context, name string) (VLANGroup, error) {
+	return findOne[VLANGroup](ctx, c, "vlan-group", fmt.Sprintf("name=%s", url.QueryEscape(name)))
+}
+
+// AddVLANGroup creates a new VLAN group
+func (c *Client) AddVLANGroup(group VLANGroupEdit) (VLANGroup, error) {
+	return c.AddVLANGroupCtx(context.Background(), group)
+}
+
+// AddVLANGroupCtx is like AddVLANGroup but uses ctx for the request.
+func (c *Client) AddVLANGroupCtx(ctx context.Context, group VLANGroupEdit) (VLANGroup, error) {
+	if group.Slug == "" {
+		group.Slug = Slugify(group.Name)
+	}
+	return createObject[VLANGroup](ctx, c, "vlan-group", group)
+}
+
+// GetOrAddVLANGroup will retrieve the requested VLAN group
+// by name and add it if it does not exist
+func (c *Client) GetOrAddVLANGroup(name string) (VLANGroup, error) {
+	return c.GetOrAddVLANGroupCtx(context.Background(), name)
+}
+
+// GetOrAddVLANGroupCtx is like GetOrAddVLANGroup but uses ctx for the requests.
+func (c *Client) GetOrAddVLANGroupCtx(ctx context.Context, name string) (VLANGroup, error) {
+	group, err := c.GetVLANGroupCtx(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddVLANGroupCtx(ctx, VLANGroupEdit{Name: name})
+	}
+	return group, err
+}
+
+// UpdateVLANGroup modifies the given fields of the VLAN group
+func (c *Client) UpdateVLANGroup(id int, group VLANGroupEdit) (VLANGroup, error) {
+	return c.UpdateVLANGroupCtx(context.Background(), id, group)
+}
+
+// UpdateVLANGroupCtx is like UpdateVLANGroup but uses ctx for the request.
+func (c *Client) UpdateVLANGroupCtx(ctx context.Context, id int, group VLANGroupEdit) (VLANGroup, error) {
+	return updateObject[VLANGroup](ctx, c, "vlan-group", id, group)
+}
+
+// DeleteVLANGroup removes the VLAN group from Netbox
+func (c *Client) DeleteVLANGroup(id int) error {
+	return c.DeleteVLANGroupCtx(context.Background(), id)
+}
+
+// DeleteVLANGroupCtx is like DeleteVLANGroup but uses ctx for the request.
+func (c *Client) DeleteVLANGroupCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "vlan-group", id)
+}
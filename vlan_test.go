@@ -0,0 +1,60 @@
+package netbox_test
+
+import (
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestVLANs(t *testing.T) {
+	srv, c := newClient(t)
+	site := srv.Add("/dcim/sites", netboxtest.Object{"name": "HQ", "slug": "hq"})
+	siteID := site["id"].(int)
+	group, err := c.GetOrAddVLANGroup("Campus")
+	if err != nil {
+		t.Fatalf("GetOrAddVLANGroup() error = %v", err)
+	}
+	inGroup, err := c.GetOrAddVLAN(netbox.VLANEdit{Vid: 10, Name: "users", Group: &group.ID})
+	if err != nil {
+		t.Fatalf("GetOrAddVLAN() error = %v", err)
+	}
+	inSite, err := c.GetOrAddVLAN(netbox.VLANEdit{Vid: 10, Name: "users", Site: &siteID})
+	if err != nil {
+		t.Fatalf("GetOrAddVLAN() error = %v", err)
+	}
+	if inGroup.ID == inSite.ID {
+		t.Errorf("GetOrAddVLAN() returned the group VLAN for the site")
+	}
+	again, err := c.GetOrAddVLAN(netbox.VLANEdit{Vid: 10, Name: "users", Group: &group.ID})
+	if err != nil || again.ID != inGroup.ID {
+		t.Errorf("GetOrAddVLAN() second call = %d, %v, want %d", again.ID, err, inGroup.ID)
+	}
+	voice, err := c.AddVLAN(netbox.VLANEdit{Vid: 20, Name: "voice", Group: &group.ID})
+	if err != nil {
+		t.Fatalf("AddVLAN() error = %v", err)
+	}
+
+	vm := srv.Add("/virtualization/virtual-machines", netboxtest.Object{"name": "fw01"})
+	edit := netbox.InterfaceEdit{}
+	edit.SetName("eth0")
+	edit.SetMode("tagged")
+	edit.SetUntaggedVlan(inGroup.ID)
+	edit.SetTaggedVlans([]int{voice.ID})
+	if _, err := c.AddInterface("virtualmachine", int64(vm["id"].(int)), edit); err != nil {
+		t.Fatalf("AddInterface() error = %v", err)
+	}
+	intf, err := c.FindInterfaceByName("virtualmachine", int64(vm["id"].(int)), "eth0")
+	if err != nil {
+		t.Fatalf("FindInterfaceByName() error = %v", err)
+	}
+	if intf.Mode == nil || intf.Mode.Value != "tagged" {
+		t.Errorf("interface mode = %+v, want tagged", intf.Mode)
+	}
+	if intf.UntaggedVlan == nil || intf.UntaggedVlan.Vid != 10 {
+		t.Errorf("untagged VLAN = %+v, want VID 10", intf.UntaggedVlan)
+	}
+	if len(intf.TaggedVlans) != 1 || intf.TaggedVlans[0].Vid != 20 {
+		t.Errorf("tagged VLANs = %+v, want VID 20", intf.TaggedVlans)
+	}
+}
@@ -0,0 +1,302 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+type VRF struct {
+	ID             int                    `json:"id"`
+	URL            string                 `json:"url"`
+	Display        string                 `json:"display"`
+	Name           string                 `json:"name"`
+	Rd             *string                `json:"rd"`
+	Tenant         *DisplayIDName         `json:"tenant"`
+	EnforceUnique  bool                   `json:"enforce_unique"`
+	Description    string                 `json:"description"`
+	Comments       string                 `json:"comments"`
+	ImportTargets  []NestedRouteTarget    `json:"import_targets"`
+	ExportTargets  []NestedRouteTarget    `json:"export_targets"`
+	Tags           []Tag                  `json:"tags"`
+	CustomFields   map[string]interface{} `json:"custom_fields"`
+	IpaddressCount int                    `json:"ipaddress_count"`
+	PrefixCount    int                    `json:"prefix_count"`
+	Created        string                 `json:"created"`
+	LastUpdated    string                 `json:"last_updated"`
+}
+
+// NestedVRF is the brief form of a VRF found on addresses, prefixes
+// and interfaces
+type NestedVRF struct {
+	ID      int     `json:"id"`
+	URL     string  `json:"url"`
+	Display string  `json:"display"`
+	Name    string  `json:"name"`
+	Rd      *string `json:"rd"`
+}
+
+// VRFEdit is used to add/update a VRF.  ImportTargets and ExportTargets
+// hold route target IDs and replace the existing targets when given.
+type VRFEdit struct {
+	Name          string                 `json:"name,omitempty"`
+	Rd            *string                `json:"rd,omitempty"`
+	Tenant        *int                   `json:"tenant,omitempty"`
+	EnforceUnique *bool                  `json:"enforce_unique,omitempty"`
+	Description   *string                `json:"description,omitempty"`
+	Comments      *string                `json:"comments,omitempty"`
+	ImportTargets []int                  `json:"import_targets,omitempty"`
+	ExportTargets []int                  `json:"export_targets,omitempty"`
+	Tags          []Tag                  `json:"tags,omitempty"`
+	CustomFields  map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+type RouteTarget struct {
+	ID           int                    `json:"id"`
+	URL          string                 `json:"url"`
+	Display      string                 `json:"display"`
+	Name         string                 `json:"name"`
+	Tenant       *DisplayIDName         `json:"tenant"`
+	Description  string                 `json:"description"`
+	Comments     string                 `json:"comments"`
+	Tags         []Tag                  `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	Created      string                 `json:"created"`
+	LastUpdated  string                 `json:"last_updated"`
+}
+
+// NestedRouteTarget is the brief form of a route target found on VRFs
+type NestedRouteTarget struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Name    string `json:"name"`
+}
+
+// RouteTargetEdit is used to add/update a route target.  Name is the
+// target in RFC 4360 format, eg. "65000:100".
+type RouteTargetEdit struct {
+	Name         string                 `json:"name,omitempty"`
+	Tenant       *int                   `json:"tenant,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	Comments     *string                `json:"comments,omitempty"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// ListVRFs returns all VRFs that match the filter.  Filter
+// needs to be given as a valid api filter (eg. tenant_id=1)
+func (c *Client) ListVRFs(filter *string) ([]VRF, error) {
+	return c.ListVRFsCtx(context.Background(), filter)
+}
+
+// ListVRFsCtx is like ListVRFs but uses ctx for the requests.
+func (c *Client) ListVRFsCtx(ctx context.Context, filter *string) ([]VRF, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	vrfs, err := listAll[VRF](ctx, c, "vrf", args)
+	if err != nil {
+		c.log.Error("error finding vrfs", "filter", filter, "error", err)
+	}
+	return vrfs, err
+}
+
+// GetVRF retrieves the VRF with the given ID
+func (c *Client) GetVRF(id int) (VRF, error) {
+	return c.GetVRFCtx(context.Background(), id)
+}
+
+// GetVRFCtx is like GetVRF but uses ctx for the request.
+func (c *Client) GetVRFCtx(ctx context.Context, id int) (VRF, error) {
+	return getObject[VRF](ctx, c, "vrf", id)
+}
+
+// FindVRF looks up the VRF by name and route distinguisher.  An empty
+// rd matches only VRFs without one.
+func (c *Client) FindVRF(name string, rd string) (VRF, error) {
+	return c.FindVRFCtx(context.Background(), name, rd)
+}
+
+// FindVRFCtx is like FindVRF but uses ctx for the requests.
+func (c *Client) FindVRFCtx(ctx context.Context, name string, rd string) (VRF, error) {
+	vrfs, err := listAll[VRF](ctx, c, "vrf", fmt.Sprintf("name=%s", url.QueryEscape(name)))
+	if err != nil {
+		return VRF{}, err
+	}
+	var found []VRF
+	for _, vrf := range vrfs {
+		if (vrf.Rd == nil && rd == "") || (vrf.Rd != nil && *vrf.Rd == rd) {
+			found = append(found, vrf)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return VRF{}, ErrNotFound
+	case 1:
+		return found[0], nil
+	}
+	return VRF{}, fmt.Errorf("%w: %d", ErrMultipleResults, len(found))
+}
+
+// AddVRF creates a new VRF
+func (c *Client) AddVRF(vrf VRFEdit) (VRF, error) {
+	return c.AddVRFCtx(context.Background(), vrf)
+}
+
+// AddVRFCtx is like AddVRF but uses ctx for the request.
+func (c *Client) AddVRFCtx(ctx context.Context, vrf VRFEdit) (VRF, error) {
+	return createObject[VRF](ctx, c, "vrf", vrf)
+}
+
+// GetOrAddVRF will retrieve the requested VRF by name and route
+// distinguisher and add it if it does not exist.  rd may be empty.
+func (c *Client) GetOrAddVRF(name string, rd string) (VRF, error) {
+	return c.GetOrAddVRFCtx(context.Background(), name, rd)
+}
+
+// GetOrAddVRFCtx is like GetOrAddVRF but uses ctx for the requests.
+func (c *Client) GetOrAddVRFCtx(ctx context.Context, name string, rd string) (VRF, error) {
+	vrf, err := c.FindVRFCtx(ctx, name, rd)
+	if errors.Is(err, ErrNotFound) {
+		edit := VRFEdit{Name: name}
+		if rd != "" {
+			edit.Rd = &rd
+		}
+		return c.AddVRFCtx(ctx, edit)
+	}
+	return vrf, err
+}
+
+// UpdateVRF modifies the given fields of the VRF
+func (c *Client) UpdateVRF(id int, vrf VRFEdit) (VRF, error) {
+	return c.UpdateVRFCtx(context.Background(), id, vrf)
+}
+
+// UpdateVRFCtx is like UpdateVRF but uses ctx for the request.
+func (c *Client) UpdateVRFCtx(ctx context.Context, id int, vrf VRFEdit) (VRF, error) {
+	return updateObject[VRF](ctx, c, "vrf", id, vrf)
+}
+
+// DeleteVRF removes the VRF from Netbox
+func (c *Client) DeleteVRF(id int) error {
+	return c.DeleteVRFCtx(context.Background(), id)
+}
+
+// DeleteVRFCtx is like DeleteVRF but uses ctx for the request.
+func (c *Client) DeleteVRFCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "vrf", id)
+}
+
+// AddVRFTargets adds the route targets, given by ID, to the import and
+// export targets of the VRF.  Targets already assigned are kept.
+func (c *Client) AddVRFTargets(vrfID int, imports []int, exports []int) (VRF, error) {
+	return c.AddVRFTargetsCtx(context.Background(), vrfID, imports, exports)
+}
+
+// AddVRFTargetsCtx is like AddVRFTargets but uses ctx for the requests.
+func (c *Client) AddVRFTargetsCtx(ctx context.Context, vrfID int, imports []int, exports []int) (VRF, error) {
+	vrf, err := c.GetVRFCtx(ctx, vrfID)
+	if err != nil {
+		return vrf, err
+	}
+	edit := VRFEdit{
+		ImportTargets: mergeTargets(vrf.ImportTargets, imports),
+		ExportTargets: mergeTargets(vrf.ExportTargets, exports),
+	}
+	return c.UpdateVRFCtx(ctx, vrfID, edit)
+}
+
+// mergeTargets returns the IDs of the current targets followed by the
+// added ones not yet present
+func mergeTargets(current []NestedRouteTarget, added []int) []int {
+	ids := make([]int, 0, len(current)+len(added))
+	seen := make(map[int]bool)
+	for _, rt := range current {
+		ids = append(ids, rt.ID)
+		seen[rt.ID] = true
+	}
+	for _, id := range added {
+		if !seen[id] {
+			ids = append(ids, id)
+			seen[id] = true
+		}
+	}
+	return ids
+}
+
+// ListRouteTargets returns all route targets that match the filter.
+// Filter needs to be given as a valid api filter (eg. importing_vrf_id=1)
+func (c *Client) ListRouteTargets(filter *string) ([]RouteTarget, error) {
+	return c.ListRouteTargetsCtx(context.Background(), filter)
+}
+
+// ListRouteTargetsCtx is like ListRouteTargets but uses ctx for the requests.
+func (c *Client) ListRouteTargetsCtx(ctx context.Context, filter *string) ([]RouteTarget, error) {
+	var args string
+	if filter != nil {
+		args = *filter
+	}
+	targets, err := listAll[RouteTarget](ctx, c, "route-target", args)
+	if err != nil {
+		c.log.Error("error finding route targets", "filter", filter, "error", err)
+	}
+	return targets, err
+}
+
+// GetRouteTarget looks up the route target by name, eg. "65000:100"
+func (c *Client) GetRouteTarget(name string) (RouteTarget, error) {
+	return c.GetRouteTargetCtx(context.Background(), name)
+}
+
+// GetRouteTargetCtx is like GetRouteTarget but uses ctx for the request.
+func (c *Client) GetRouteTargetCtx(ctx context.Context, name string) (RouteTarget, error) {
+	return findOne[RouteTarget](ctx, c, "route-target", fmt.Sprintf("name=%s", url.QueryEscape(name)))
+}
+
+// AddRouteTarget creates a new route target
+func (c *Client) AddRouteTarget(target RouteTargetEdit) (RouteTarget, error) {
+	return c.AddRouteTargetCtx(context.Background(), target)
+}
+
+// AddRouteTargetCtx is like AddRouteTarget but uses ctx for the request.
+func (c *Client) AddRouteTargetCtx(ctx context.Context, target RouteTargetEdit) (RouteTarget, error) {
+	return createObject[RouteTarget](ctx, c, "route-target", target)
+}
+
+// GetOrAddRouteTarget will retrieve the requested route target
+// by name and add it if it does not exist
+func (c *Client) GetOrAddRouteTarget(name string) (RouteTarget, error) {
+	return c.GetOrAddRouteTargetCtx(context.Background(), name)
+}
+
+// GetOrAddRouteTargetCtx is like GetOrAddRouteTarget but uses ctx for the requests.
+func (c *Client) GetOrAddRouteTargetCtx(ctx context.Context, name string) (RouteTarget, error) {
+	target, err := c.GetRouteTargetCtx(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return c.AddRouteTargetCtx(ctx, RouteTargetEdit{Name: name})
+	}
+	return target, err
+}
+
+// UpdateRouteTarget modifies the given fields of the route target
+func (c *Client) UpdateRouteTarget(id int, target RouteTargetEdit) (RouteTarget, error) {
+	return c.UpdateRouteTargetCtx(context.Background(), id, target)
+}
+
+// UpdateRouteTargetCtx is like UpdateRouteTarget but uses ctx for the request.
+func (c *Client) UpdateRouteTargetCtx(ctx context.Context, id int, target RouteTargetEdit) (RouteTarget, error) {
+	return updateObject[RouteTarget](ctx, c, "route-target", id, target)
+}
+
+// DeleteRouteTarget removes the route target from Netbox
+func (c *Client) DeleteRouteTarget(id int) error {
+	return c.DeleteRouteTargetCtx(context.Background(), id)
+}
+
+// DeleteRouteTargetCtx is like DeleteRouteTarget but uses ctx for the request.
+func (c *Client) DeleteRouteTargetCtx(ctx context.Context, id int) error {
+	return c.deleteObject(ctx, "route-target", id)
+}
@@ -0,0 +1,61 @@
+package netbox_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/rsapc/netbox"
+	"github.com/rsapc/netbox/netboxtest"
+)
+
+func TestVRFs(t *testing.T) {
+	srv, c := newClient(t)
+	vrf, err := c.GetOrAddVRF("customer-a", "65000:1")
+	if err != nil {
+		t.Fatalf("GetOrAddVRF() error = %v", err)
+	}
+	again, err := c.GetOrAddVRF("customer-a", "65000:1")
+	if err != nil || again.ID != vrf.ID {
+		t.Errorf("GetOrAddVRF() second call = %d, %v, want %d", again.ID, err, vrf.ID)
+	}
+	if other, err := c.GetOrAddVRF("customer-a", ""); err != nil || other.ID == vrf.ID {
+		t.Errorf("GetOrAddVRF() without rd = %d, %v, want a new VRF", other.ID, err)
+	}
+
+	rt, err := c.GetOrAddRouteTarget("65000:100")
+	if err != nil {
+		t.Fatalf("GetOrAddRouteTarget() error = %v", err)
+	}
+	vrf, err = c.AddVRFTargets(vrf.ID, []int{rt.ID}, []int{rt.ID})
+	if err != nil {
+		t.Fatalf("AddVRFTargets() error = %v", err)
+	}
+	if len(vrf.ImportTargets) != 1 || vrf.ImportTargets[0].Name != "65000:100" || len(vrf.ExportTargets) != 1 {
+		t.Errorf("AddVRFTargets() = %+v / %+v", vrf.ImportTargets, vrf.ExportTargets)
+	}
+
+	prefix, err := c.AddPrefix(netbox.PrefixEdit{Prefix: "10.0.0.0/24", Vrf: &vrf.ID})
+	if err != nil {
+		t.Fatalf("AddPrefix() error = %v", err)
+	}
+	srv.Add("/ipam/prefixes", netboxtest.Object{"prefix": "10.0.0.0/24"})
+	ip, err := c.AllocateNextIP(prefix.ID, netbox.NextIPOptions{})
+	if err != nil {
+		t.Fatalf("AllocateNextIP() error = %v", err)
+	}
+	if ip.Vrf == nil || ip.Vrf.ID != vrf.ID {
+		t.Errorf("AllocateNextIP() vrf = %+v, want %d", ip.Vrf, vrf.ID)
+	}
+	global := 0
+	if _, err := c.CreateIP(netbox.IPEdit{Address: "10.0.0.1/24"}); err != nil {
+		t.Fatalf("CreateIP() error = %v", err)
+	}
+	ips, err := c.SearchIPs(netbox.IPQuery{Addr: netip.MustParseAddr("10.0.0.1"), VrfID: &vrf.ID})
+	if err != nil || len(ips) != 1 || ips[0].ID != ip.ID {
+		t.Errorf("SearchIPs(vrf) = %+v, %v", ips, err)
+	}
+	prefixes, err := c.SearchPrefixes(netbox.PrefixQuery{Contains: netip.MustParseAddr("10.0.0.7"), VrfID: &global})
+	if err != nil || len(prefixes) != 1 || prefixes[0].ID == prefix.ID {
+		t.Errorf("SearchPrefixes(global) = %+v, %v", prefixes, err)
+	}
+}